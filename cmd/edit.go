@@ -5,7 +5,6 @@ import (
 	"os"
 	"os/exec"
 
-	"github.com/frontendtony/shepherd/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -14,10 +13,7 @@ var editCmd = &cobra.Command{
 	Short: "Open the config file in your editor",
 	Long:  `Opens the shepherd config file in $EDITOR (falls back to nano).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfgPath := configPath
-		if cfgPath == "" {
-			cfgPath = config.DefaultConfigPath()
-		}
+		cfgPath := resolveConfigPath()
 
 		editor := os.Getenv("EDITOR")
 		if editor == "" {
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Print the dependency graph in Graphviz DOT format",
+	Long: `Prints the processes and their dependencies as a Graphviz DOT graph, with
+processes clustered by group and optional dependencies dashed. Render it with,
+for example, shepherd graph | dot -Tpng -o deps.png.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(resolveConfigPath())
+		if err != nil {
+			return err
+		}
+		fmt.Print(process.NewDependencyGraph(cfg).DOT(cfg.Groups))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/spf13/cobra"
+)
+
+var listJSON bool
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the configured stacks, groups, and processes",
+	Long: `Prints the config's stacks with their groups, groups with their processes,
+and every process with its dependencies, without starting anything. Processes
+are listed in the order shepherd would start them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(resolveConfigPath())
+		if err != nil {
+			return err
+		}
+
+		t, err := buildTopology(cfg)
+		if err != nil {
+			return err
+		}
+
+		if listJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(t)
+		}
+		t.print(os.Stdout)
+		return nil
+	},
+}
+
+type topology struct {
+	Stacks    []topologyStack   `json:"stacks"`
+	Groups    []topologyGroup   `json:"groups"`
+	Processes []topologyProcess `json:"processes"`
+}
+
+type topologyStack struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Groups      []string `json:"groups"`
+}
+
+type topologyGroup struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Processes   []string `json:"processes"`
+	DependsOn   []string `json:"depends_on,omitempty"`
+}
+
+type topologyProcess struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	DependsOn   []topologyDependency `json:"depends_on,omitempty"`
+}
+
+type topologyDependency struct {
+	Name      string `json:"name"`
+	Optional  bool   `json:"optional,omitempty"`
+	Condition string `json:"condition,omitempty"`
+}
+
+// buildTopology collects stacks and groups sorted by name, and processes in
+// start order.
+func buildTopology(cfg *config.Config) (*topology, error) {
+	t := &topology{
+		Stacks:    []topologyStack{},
+		Groups:    []topologyGroup{},
+		Processes: []topologyProcess{},
+	}
+
+	for _, name := range sortedKeys(cfg.Stacks) {
+		s := cfg.Stacks[name]
+		t.Stacks = append(t.Stacks, topologyStack{Name: name, Description: s.Description, Groups: s.Groups})
+	}
+	for _, name := range sortedKeys(cfg.Groups) {
+		g := cfg.Groups[name]
+		t.Groups = append(t.Groups, topologyGroup{Name: name, Description: g.Description, Processes: g.Processes, DependsOn: g.DependsOn})
+	}
+
+	levels, err := process.NewDependencyGraph(cfg).StartLevels(sortedKeys(cfg.Processes))
+	if err != nil {
+		return nil, err
+	}
+	for _, level := range levels {
+		for _, name := range level {
+			p := cfg.Processes[name]
+			tp := topologyProcess{Name: name, Description: p.Description, Tags: p.Tags}
+			for _, dep := range p.DependsOn {
+				tp.DependsOn = append(tp.DependsOn, topologyDependency(dep))
+			}
+			t.Processes = append(t.Processes, tp)
+		}
+	}
+	return t, nil
+}
+
+func (t *topology) print(w io.Writer) {
+	fmt.Fprintln(w, "Stacks")
+	for _, s := range t.Stacks {
+		fmt.Fprintf(w, "  %s\n", withDescription(s.Name, s.Description))
+		for _, g := range s.Groups {
+			fmt.Fprintf(w, "    %s\n", g)
+		}
+	}
+
+	fmt.Fprintln(w, "Groups")
+	for _, g := range t.Groups {
+		fmt.Fprintf(w, "  %s\n", withDescription(g.Name, g.Description))
+		for _, p := range g.Processes {
+			fmt.Fprintf(w, "    %s\n", p)
+		}
+		if len(g.DependsOn) > 0 {
+			fmt.Fprintf(w, "    starts after %s\n", strings.Join(g.DependsOn, ", "))
+		}
+	}
+
+	fmt.Fprintln(w, "Processes (start order)")
+	for _, p := range t.Processes {
+		fmt.Fprintf(w, "  %s\n", withDescription(p.Name, p.Description))
+		if len(p.Tags) > 0 {
+			fmt.Fprintf(w, "    tags: %s\n", strings.Join(p.Tags, ", "))
+		}
+		for _, dep := range p.DependsOn {
+			var notes []string
+			if dep.Optional {
+				notes = append(notes, "optional")
+			}
+			if dep.Condition != "" {
+				notes = append(notes, dep.Condition)
+			}
+			line := "depends on " + dep.Name
+			if len(notes) > 0 {
+				line += " (" + strings.Join(notes, ", ") + ")"
+			}
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+}
+
+func withDescription(name, description string) string {
+	if description == "" {
+		return name
+	}
+	return name + "  " + description
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "print the topology as JSON")
+	rootCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/frontendtony/shepherd/internal/control"
+	"github.com/spf13/cobra"
+)
+
+// logsPollInterval is how often `shepherd logs -f` asks for new lines.
+const logsPollInterval = 500 * time.Millisecond
+
+var (
+	logsTail   int
+	logsFollow bool
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <process>",
+	Short: "Print a process's buffered log output",
+	Long: `Prints the log lines a running shepherd instance has buffered for a process,
+read through its control socket. With -f, keeps printing new lines as they
+arrive until interrupted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(resolveConfigPath())
+		if err != nil {
+			return err
+		}
+		if logsTail < 0 {
+			return fmt.Errorf("--tail must not be negative")
+		}
+		name := args[0]
+		if _, ok := cfg.Processes[name]; !ok {
+			return fmt.Errorf("unknown process: %s", name)
+		}
+
+		socket := cfg.Control.Socket
+		resp, err := control.Call(socket, control.Request{Action: control.ActionLogs, Name: name, Tail: logsTail})
+		if err != nil {
+			return fmt.Errorf("no running shepherd instance: %w", err)
+		}
+		if !resp.OK {
+			return fmt.Errorf("reading logs: %s", resp.Error)
+		}
+		printLines(resp.Lines)
+		if !logsFollow {
+			return nil
+		}
+
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		ticker := time.NewTicker(logsPollInterval)
+		defer ticker.Stop()
+
+		seq := resp.Seq
+		for {
+			select {
+			case <-sigCh:
+				return nil
+			case <-ticker.C:
+			}
+			resp, err := control.Call(socket, control.Request{Action: control.ActionLogs, Name: name, Since: seq})
+			if err != nil {
+				return fmt.Errorf("shepherd instance went away: %w", err)
+			}
+			if !resp.OK {
+				return fmt.Errorf("reading logs: %s", resp.Error)
+			}
+			printLines(resp.Lines)
+			seq = resp.Seq
+		}
+	},
+}
+
+func printLines(lines []string) {
+	for _, l := range lines {
+		fmt.Println(l)
+	}
+}
+
+func init() {
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "n", 0, "only print the last N lines (0 for all buffered lines)")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep printing new lines as they arrive")
+	rootCmd.AddCommand(logsCmd)
+}
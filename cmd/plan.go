@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/frontendtony/shepherd/internal/process"
+)
+
+// printStartPlan prints the processes that starting target would launch, in
+// start order, with what each one waits for.
+func printStartPlan(w io.Writer, cfg *config.Config, target string, order []string) {
+	graph := process.NewDependencyGraph(cfg)
+	inPlan := make(map[string]bool, len(order))
+	for _, name := range order {
+		inPlan[name] = true
+	}
+
+	width := 0
+	for _, name := range order {
+		width = max(width, len(name))
+	}
+
+	fmt.Fprintf(w, "Start plan for %s (%d processes)\n", target, len(order))
+	for i, name := range order {
+		proc := cfg.Processes[name]
+
+		var after []string
+		for _, dep := range proc.DependsOn {
+			var notes []string
+			if dep.Optional {
+				notes = append(notes, "optional")
+			}
+			if dep.Condition == config.DependencyStarted {
+				notes = append(notes, "started")
+			}
+			after = append(after, withNotes(dep.Name, notes))
+		}
+		for _, other := range graph.After(name) {
+			if inPlan[other] && !dependsOn(proc, other) {
+				after = append(after, withNotes(other, []string{"group order"}))
+			}
+		}
+
+		line := fmt.Sprintf("%3d. %s", i+1, name)
+		if len(after) > 0 {
+			line = fmt.Sprintf("%3d. %-*s  after %s", i+1, width, name, strings.Join(after, ", "))
+		}
+		if proc.Schedule != "" {
+			line += fmt.Sprintf("  (scheduled: %s)", proc.Schedule)
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func dependsOn(proc config.Process, name string) bool {
+	for _, dep := range proc.DependsOn {
+		if dep.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func withNotes(name string, notes []string) string {
+	if len(notes) == 0 {
+		return name
+	}
+	return name + " (" + strings.Join(notes, ", ") + ")"
+}
@@ -3,21 +3,36 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/frontendtony/shepherd/internal/api"
 	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/frontendtony/shepherd/internal/control"
+	"github.com/frontendtony/shepherd/internal/metrics"
+	"github.com/frontendtony/shepherd/internal/notify"
 	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/frontendtony/shepherd/internal/session"
 	"github.com/frontendtony/shepherd/internal/tui"
+	"github.com/frontendtony/shepherd/internal/webhook"
 	"github.com/spf13/cobra"
 )
 
 var (
-	configPath string
-	verbose    bool
+	configPath  string
+	verbose     bool
+	headless    bool
+	listenAddr  string
+	metricsAddr string
+	notifyFlag  bool
+	startTag    string
+	dryRun      bool
 )
 
 var rootCmd = &cobra.Command{
@@ -27,15 +42,17 @@ var rootCmd = &cobra.Command{
 ensuring none stray, and bringing back any that wander off.
 
 Run without arguments to open the TUI. Optionally pass a stack,
-group, or process name to auto-start it on launch.`,
+group, or process name to auto-start it on launch, or use --tag (or
+the name tag:<tag>) to auto-start every process carrying a tag.
+
+With --headless, no TUI is shown; shepherd runs in the foreground and
+is controlled through its control socket until it receives SIGINT or
+SIGTERM.`,
 	Args:          cobra.MaximumNArgs(1),
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfgPath := configPath
-		if cfgPath == "" {
-			cfgPath = config.DefaultConfigPath()
-		}
+		cfgPath := resolveConfigPath()
 
 		// First-run: generate example config if none exists.
 		if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
@@ -50,13 +67,36 @@ group, or process name to auto-start it on launch.`,
 			return nil
 		}
 
-		cfg, err := config.Load(cfgPath)
+		cfg, err := loadConfig(cfgPath)
 		if err != nil {
-			return fmt.Errorf("loading config: %w", err)
-		}
-		if err := config.Validate(cfg); err != nil {
 			return err
 		}
+		for _, w := range config.Warnings(cfg) {
+			slog.Warn("config warning", "warning", w)
+		}
+
+		var autoStart string
+		if len(args) == 1 {
+			autoStart = args[0]
+		}
+		if startTag != "" {
+			if autoStart != "" {
+				return fmt.Errorf("give either a name or --tag, not both")
+			}
+			autoStart = config.TagPrefix + startTag
+		}
+
+		if dryRun {
+			if autoStart == "" {
+				return fmt.Errorf("--dry-run needs a name or --tag to plan")
+			}
+			order, err := process.StartPlan(cfg, autoStart)
+			if err != nil {
+				return err
+			}
+			printStartPlan(os.Stdout, cfg, autoStart, order)
+			return nil
+		}
 
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -69,7 +109,8 @@ group, or process name to auto-start it on launch.`,
 			cancel()
 		}()
 
-		// Placeholder for SIGHUP handler (wired after Program is created).
+		// SIGHUP reloads the config; the handler is wired up below, once it
+		// is known whether the TUI runs.
 		sigHup := make(chan os.Signal, 1)
 		signal.Notify(sigHup, syscall.SIGHUP)
 
@@ -79,12 +120,80 @@ group, or process name to auto-start it on launch.`,
 		}
 		defer mgr.Shutdown()
 
-		var autoStart string
-		if len(args) == 1 {
-			autoStart = args[0]
+		if cfg.Control.Enabled || headless {
+			srv := control.NewServer(mgr, cfg.Control.Socket)
+			if err := srv.Start(); err != nil {
+				return fmt.Errorf("starting control server: %w", err)
+			}
+			defer srv.Close()
+		}
+
+		if listenAddr != "" {
+			srv, err := serveHTTP(listenAddr, api.NewHandler(mgr))
+			if err != nil {
+				return fmt.Errorf("starting HTTP API: %w", err)
+			}
+			defer srv.Close()
+		}
+
+		if metricsAddr != "" {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metrics.Handler(mgr))
+			srv, err := serveHTTP(metricsAddr, mux)
+			if err != nil {
+				return fmt.Errorf("starting metrics server: %w", err)
+			}
+			defer srv.Close()
+		}
+
+		if notifyFlag || cfg.Notify {
+			n, err := notify.New()
+			if err != nil {
+				slog.Warn("notifications disabled", "error", err)
+			} else {
+				events, unsubscribe := mgr.Subscribe()
+				defer unsubscribe()
+				go n.Run(ctx, events)
+			}
+		}
+
+		if len(cfg.Webhooks) > 0 {
+			events, unsubscribe := mgr.Subscribe()
+			defer unsubscribe()
+			go webhook.NewDispatcher(cfg.Webhooks).Run(ctx, events)
+		}
+
+		var restore []string
+		if cfg.Session.Restore {
+			restore, err = session.Load(cfg.Session.File)
+			if err != nil {
+				slog.Warn("not restoring session", "error", err)
+			}
 		}
 
-		model := tui.NewModel(mgr, cfg, autoStart)
+		if headless {
+			if autoStart != "" {
+				res, err := mgr.StartByName(autoStart)
+				if err == nil {
+					err = res.Err()
+				}
+				if err != nil {
+					return fmt.Errorf("starting %s: %w", autoStart, err)
+				}
+			} else {
+				restoreSession(mgr, restore)
+			}
+			go reloadOnHangup(mgr, cfgPath, sigHup)
+			<-ctx.Done()
+			if cfg.Session.Restore {
+				if err := session.Save(cfg.Session.File, mgr.RunningNames()); err != nil {
+					slog.Warn("failed to save session", "error", err)
+				}
+			}
+			return nil
+		}
+
+		model := tui.NewModel(mgr, cfg, autoStart, restore)
 		p := tea.NewProgram(model, tea.WithAltScreen())
 
 		// SIGHUP: reload config and notify TUI.
@@ -99,7 +208,12 @@ group, or process name to auto-start it on launch.`,
 					p.Send(tui.NotifyMsg{Text: fmt.Sprintf("Config invalid: %s", err)})
 					continue
 				}
-				p.Send(tui.ConfigReloadMsg{Config: newCfg})
+				oldCfg := mgr.GetConfig()
+				if err := mgr.ApplyConfig(newCfg); err != nil {
+					p.Send(tui.NotifyMsg{Text: fmt.Sprintf("Config invalid: %s", err)})
+					continue
+				}
+				p.Send(tui.ConfigReloadMsg{Config: newCfg, Changed: config.DiffConfigs(oldCfg, newCfg)})
 			}
 		}()
 
@@ -114,6 +228,80 @@ group, or process name to auto-start it on launch.`,
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "path to config file (default: ~/.config/shepherd/config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable debug logging")
+	rootCmd.Flags().StringVar(&listenAddr, "listen", "", "serve the HTTP API on this address (e.g. :8080)")
+	rootCmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "serve Prometheus metrics at /metrics on this address (e.g. :9090)")
+	rootCmd.Flags().BoolVar(&notifyFlag, "notify", false, "show a desktop notification when a process fails after exhausting its retries")
+	rootCmd.Flags().BoolVar(&headless, "headless", false, "run without the TUI, controlled via the control socket")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the order the named target would start in, then exit without starting anything")
+	rootCmd.Flags().StringVar(&startTag, "tag", "", "auto-start every process with this tag, plus their dependencies")
+}
+
+// serveHTTP starts an HTTP server on addr in the background. Binding errors are
+// returned immediately; later serve errors are logged.
+func serveHTTP(addr string, handler http.Handler) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Addr: addr, Handler: handler}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			slog.Error("HTTP server stopped", "addr", addr, "error", err)
+		}
+	}()
+	return srv, nil
+}
+
+// restoreSession starts the processes saved by the previous session, skipping
+// any that are no longer in the config.
+func restoreSession(mgr *process.ProcessManager, names []string) {
+	for _, name := range names {
+		if _, ok := mgr.GetConfig().Processes[name]; !ok {
+			continue
+		}
+		if err := mgr.StartProcess(name); err != nil {
+			slog.Warn("failed to restore process", "process", name, "error", err)
+		}
+	}
+}
+
+// reloadOnHangup reloads the config at path each time a signal arrives on
+// sig, for headless mode, where there is no TUI to report to; the outcome is
+// logged instead.
+func reloadOnHangup(mgr *process.ProcessManager, path string, sig <-chan os.Signal) {
+	for range sig {
+		newCfg, err := loadConfig(path)
+		if err != nil {
+			slog.Error("config reload failed", "error", err)
+			continue
+		}
+		oldCfg := mgr.GetConfig()
+		if err := mgr.ApplyConfig(newCfg); err != nil {
+			slog.Error("config reload failed", "error", err)
+			continue
+		}
+		slog.Info("config reloaded", "path", path, "changed", config.DiffConfigs(oldCfg, newCfg))
+	}
+}
+
+// resolveConfigPath returns the --config flag value or the default location.
+func resolveConfigPath() string {
+	if configPath != "" {
+		return configPath
+	}
+	return config.DefaultConfigPath()
+}
+
+// loadConfig loads and validates the config at path.
+func loadConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if err := config.Validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 func Execute() {
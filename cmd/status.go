@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/frontendtony/shepherd/internal/control"
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [process...]",
+	Short: "Print process states as JSON",
+	Long: `Prints the state of each configured process (or only the named ones) as a
+JSON array sorted by name. Exits non-zero unless every listed process is running.
+
+States are read from a running instance through its control socket. If no
+instance is reachable, every process is reported as stopped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(resolveConfigPath())
+		if err != nil {
+			return err
+		}
+
+		names := args
+		if len(names) == 0 {
+			for name := range cfg.Processes {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		live := make(map[string]process.ProcessState)
+		if resp, err := control.Call(cfg.Control.Socket, control.Request{Action: control.ActionStatus}); err == nil {
+			if !resp.OK {
+				return fmt.Errorf("querying status: %s", resp.Error)
+			}
+			for _, s := range resp.States {
+				live[s.Name] = s
+			}
+		}
+
+		states := make([]process.ProcessState, 0, len(names))
+		for _, name := range names {
+			if _, ok := cfg.Processes[name]; !ok {
+				return fmt.Errorf("unknown process: %s", name)
+			}
+			state, ok := live[name]
+			if !ok {
+				state = process.ProcessState{Name: name, Status: process.StatusStopped}
+			}
+			states = append(states, state)
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(states); err != nil {
+			return fmt.Errorf("encoding status: %w", err)
+		}
+
+		for _, s := range states {
+			if !s.Status.IsRunning() {
+				return fmt.Errorf("process %s is %s", s.Name, s.Status)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
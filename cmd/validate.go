@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var validateStrict bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for errors",
+	Long: `Loads and validates the config file without starting anything. Warnings,
+such as processes that belong to no group or a working_dir that doesn't exist
+yet, are printed but don't fail validation unless --strict is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := resolveConfigPath()
+		cfg, err := loadConfig(path)
+		if err != nil {
+			return err
+		}
+
+		warnings := config.Warnings(cfg)
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+		if validateStrict && len(warnings) > 0 {
+			return fmt.Errorf("%d warning(s) in strict mode", len(warnings))
+		}
+
+		fmt.Printf("%s is valid\n", path)
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "treat warnings as errors")
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,131 @@
+// Package api exposes a ProcessManager over HTTP for remote monitoring.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/frontendtony/shepherd/internal/process"
+)
+
+// NewHandler returns an http.Handler serving the shepherd HTTP API:
+//
+//	GET  /processes                    all process states, sorted by name
+//	GET  /processes/{name}/logs?tail=N recent log lines (all if tail is unset)
+//	POST /processes/{name}/start       start a process and its dependencies
+//	POST /processes/{name}/stop        stop a process and its dependents
+//	POST /processes/{name}/restart     restart a process
+//	GET  /events                       server-sent stream of state events
+func NewHandler(mgr *process.ProcessManager) http.Handler {
+	h := &handler{mgr: mgr}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /processes", h.listProcesses)
+	mux.HandleFunc("GET /processes/{name}/logs", h.logs)
+	mux.HandleFunc("POST /processes/{name}/{action}", h.action)
+	mux.HandleFunc("GET /events", h.events)
+	return mux
+}
+
+type handler struct {
+	mgr *process.ProcessManager
+}
+
+func (h *handler) listProcesses(w http.ResponseWriter, r *http.Request) {
+	states := h.mgr.GetAllStates()
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	writeJSON(w, http.StatusOK, states)
+}
+
+func (h *handler) logs(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	buf := h.mgr.GetLogBuffer(name)
+	if buf == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown process: %s", name))
+		return
+	}
+
+	tail := 0
+	if v := r.URL.Query().Get("tail"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid tail: %q", v))
+			return
+		}
+		tail = n
+	}
+
+	lines := buf.Lines(tail)
+	if lines == nil {
+		lines = []string{}
+	}
+	writeJSON(w, http.StatusOK, lines)
+}
+
+func (h *handler) action(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if h.mgr.GetLogBuffer(name) == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown process: %s", name))
+		return
+	}
+
+	var err error
+	switch action := r.PathValue("action"); action {
+	case "start":
+		err = h.mgr.StartProcess(name)
+	case "stop":
+		err = h.mgr.StopProcess(name)
+	case "restart":
+		err = h.mgr.RestartProcess(name)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown action: %s", action))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+
+	events, unsubscribe := h.mgr.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-events:
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
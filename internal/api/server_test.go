@@ -0,0 +1,123 @@
+//go:build !ci
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *process.ProcessManager) {
+	t.Helper()
+
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"sleeper": {Command: "sleep 3600"},
+			"echo":    {Command: "echo hello"},
+		},
+	}
+	mgr, err := process.NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(mgr.Shutdown)
+
+	srv := httptest.NewServer(NewHandler(mgr))
+	t.Cleanup(srv.Close)
+	return srv, mgr
+}
+
+func TestAPI_ListAndActions(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	resp, err := http.Post(srv.URL+"/processes/sleeper/start", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err = http.Get(srv.URL + "/processes")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var states []process.ProcessState
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&states))
+	require.Len(t, states, 2)
+	assert.Equal(t, "echo", states[0].Name)
+	assert.Equal(t, "sleeper", states[1].Name)
+	assert.Equal(t, process.StatusRunning, states[1].Status)
+
+	resp, err = http.Post(srv.URL+"/processes/sleeper/stop", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestAPI_UnknownProcess(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	resp, err := http.Post(srv.URL+"/processes/nope/start", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(srv.URL + "/processes/nope/logs")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestAPI_LogsTail(t *testing.T) {
+	srv, mgr := newTestServer(t)
+
+	buf := mgr.GetLogBuffer("echo")
+	buf.WriteString("one")
+	buf.WriteString("two")
+	buf.WriteString("three")
+
+	resp, err := http.Get(srv.URL + "/processes/echo/logs?tail=2")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var lines []string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&lines))
+	assert.Equal(t, []string{"two", "three"}, lines)
+}
+
+func TestAPI_EventStream(t *testing.T) {
+	srv, mgr := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.NoError(t, mgr.StartProcess("sleeper"))
+
+	lines := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	select {
+	case line := <-lines:
+		require.True(t, strings.HasPrefix(line, "data: "), line)
+		var ev process.StateEvent
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev))
+		assert.Equal(t, "sleeper", ev.Name)
+		assert.Equal(t, process.StatusRunning, ev.NewState)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
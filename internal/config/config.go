@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -18,6 +19,24 @@ func DefaultConfigPath() string {
 	return filepath.Join(home, ".config", "shepherd", "config.yaml")
 }
 
+// DefaultSocketPath returns the default control socket location.
+func DefaultSocketPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "shepherd.sock"
+	}
+	return filepath.Join(home, ".config", "shepherd", "shepherd.sock")
+}
+
+// DefaultSessionPath returns the default location of the session state file.
+func DefaultSessionPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "session.json"
+	}
+	return filepath.Join(home, ".config", "shepherd", "session.json")
+}
+
 // Load reads and parses a YAML config file. It applies defaults and expands
 // environment variables and ~ in paths.
 func Load(path string) (*Config, error) {
@@ -33,6 +52,11 @@ func Load(path string) (*Config, error) {
 
 	applyDefaults(&cfg)
 	expandPaths(&cfg)
+	renderCommands(&cfg)
+	if err := loadEnvFiles(&cfg); err != nil {
+		return nil, fmt.Errorf("loading env file: %w", err)
+	}
+	applyGlobalEnv(&cfg)
 
 	return &cfg, nil
 }
@@ -42,6 +66,22 @@ func Load(path string) (*Config, error) {
 func Validate(cfg *Config) error {
 	var errs []string
 
+	if cfg.Version != 0 && cfg.Version != CurrentVersion {
+		errs = append(errs, fmt.Sprintf("unsupported config version %d, this build supports version %d", cfg.Version, CurrentVersion))
+	}
+	if cfg.StartLimit < 0 {
+		errs = append(errs, "max_concurrent_starts must not be negative")
+	}
+	if cfg.HealthTimeout < 0 {
+		errs = append(errs, "health_timeout must not be negative")
+	}
+	if cfg.HealthPoll < 0 {
+		errs = append(errs, "health_poll_interval must not be negative")
+	}
+	if _, err := ParseTimestampFormat(cfg.LogTimestamp); err != nil {
+		errs = append(errs, err.Error())
+	}
+
 	// Collect all names to check for uniqueness across types.
 	allNames := make(map[string]string) // name -> type ("stack", "group", "process")
 
@@ -71,6 +111,9 @@ func Validate(cfg *Config) error {
 				errs = append(errs, fmt.Sprintf("stack %q references undefined group %q", stackName, groupName))
 			}
 		}
+		for _, dup := range duplicates(stack.Groups) {
+			errs = append(errs, fmt.Sprintf("stack %q lists group %q more than once", stackName, dup))
+		}
 	}
 
 	// Validate group references.
@@ -80,11 +123,25 @@ func Validate(cfg *Config) error {
 				errs = append(errs, fmt.Sprintf("group %q references undefined process %q", groupName, procName))
 			}
 		}
+		for _, dup := range duplicates(group.Processes) {
+			errs = append(errs, fmt.Sprintf("group %q lists process %q more than once", groupName, dup))
+		}
+		for _, dep := range group.DependsOn {
+			if _, ok := cfg.Groups[dep]; !ok {
+				errs = append(errs, fmt.Sprintf("group %q depends on undefined group %q", groupName, dep))
+			}
+			if dep == groupName {
+				errs = append(errs, fmt.Sprintf("group %q depends on itself", groupName))
+			}
+		}
+		for _, dup := range duplicates(group.DependsOn) {
+			errs = append(errs, fmt.Sprintf("group %q lists dependency %q more than once", groupName, dup))
+		}
 	}
 
 	// Validate dependency references.
 	for procName, proc := range cfg.Processes {
-		for _, dep := range proc.DependsOn {
+		for _, dep := range proc.DependencyNames() {
 			if _, ok := cfg.Processes[dep]; !ok {
 				errs = append(errs, fmt.Sprintf("process %q depends on undefined process %q", procName, dep))
 			}
@@ -92,31 +149,219 @@ func Validate(cfg *Config) error {
 				errs = append(errs, fmt.Sprintf("process %q depends on itself", procName))
 			}
 		}
+		for _, dup := range duplicates(proc.DependencyNames()) {
+			errs = append(errs, fmt.Sprintf("process %q lists dependency %q more than once", procName, dup))
+		}
+		for _, dep := range proc.DependsOn {
+			switch dep.Condition {
+			case "", DependencyHealthy, DependencyStarted:
+			default:
+				errs = append(errs, fmt.Sprintf("process %q: condition for dependency %q must be %s or %s (got %q)",
+					procName, dep.Name, DependencyHealthy, DependencyStarted, dep.Condition))
+			}
+		}
+	}
+
+	// Validate tags.
+	for procName, proc := range cfg.Processes {
+		for _, tag := range proc.Tags {
+			if tag == "" || strings.ContainsAny(tag, " \t") {
+				errs = append(errs, fmt.Sprintf("process %q: tag %q must be non-empty and contain no spaces", procName, tag))
+			}
+		}
+		for _, dup := range duplicates(proc.Tags) {
+			errs = append(errs, fmt.Sprintf("process %q lists tag %q more than once", procName, dup))
+		}
 	}
 
 	// Validate retry config values.
 	for procName, proc := range cfg.Processes {
+		switch proc.Retry.Mode {
+		case "", RetryExponential, RetryFixed:
+		default:
+			errs = append(errs, fmt.Sprintf("process %q: retry mode must be %s or %s (got %q)",
+				procName, RetryExponential, RetryFixed, proc.Retry.Mode))
+		}
 		if proc.Retry.Enabled {
 			if proc.Retry.InitialBackoff.Duration() <= 0 {
 				errs = append(errs, fmt.Sprintf("process %q: initial_backoff must be positive", procName))
 			}
-			if proc.Retry.MaxBackoff.Duration() <= 0 {
-				errs = append(errs, fmt.Sprintf("process %q: max_backoff must be positive", procName))
+			// Fixed mode never grows the backoff, so max_backoff and
+			// backoff_multiplier don't apply.
+			if proc.Retry.Mode != RetryFixed {
+				if proc.Retry.MaxBackoff.Duration() <= 0 {
+					errs = append(errs, fmt.Sprintf("process %q: max_backoff must be positive", procName))
+				}
+				if proc.Retry.InitialBackoff.Duration() > proc.Retry.MaxBackoff.Duration() {
+					errs = append(errs, fmt.Sprintf("process %q: initial_backoff (%s) must be <= max_backoff (%s)",
+						procName, proc.Retry.InitialBackoff.Duration(), proc.Retry.MaxBackoff.Duration()))
+				}
+				if proc.Retry.BackoffMultiplier < 1 {
+					errs = append(errs, fmt.Sprintf("process %q: backoff_multiplier must be >= 1", procName))
+				}
 			}
-			if proc.Retry.InitialBackoff.Duration() > proc.Retry.MaxBackoff.Duration() {
-				errs = append(errs, fmt.Sprintf("process %q: initial_backoff (%s) must be <= max_backoff (%s)",
-					procName, proc.Retry.InitialBackoff.Duration(), proc.Retry.MaxBackoff.Duration()))
+			if proc.Retry.ResetAfter < 0 {
+				errs = append(errs, fmt.Sprintf("process %q: reset_after must not be negative", procName))
 			}
-			if proc.Retry.BackoffMultiplier < 1 {
-				errs = append(errs, fmt.Sprintf("process %q: backoff_multiplier must be >= 1", procName))
+			if j := proc.Retry.JitterFraction(); j < 0 || j >= 1 {
+				errs = append(errs, fmt.Sprintf("process %q: jitter must be at least 0 and less than 1", procName))
 			}
 		}
 
-		if proc.Command == "" {
+		if proc.Command == "" && len(proc.Args) == 0 {
 			errs = append(errs, fmt.Sprintf("process %q: command is required", procName))
 		}
+		if len(proc.Args) > 0 && proc.Args[0] == "" {
+			errs = append(errs, fmt.Sprintf("process %q: command list must start with a program name", procName))
+		}
+	}
+
+	// Validate env files.
+	if cfg.EnvFile != "" {
+		if info, err := os.Stat(cfg.EnvFile); err != nil {
+			errs = append(errs, fmt.Sprintf("env_file %s does not exist", cfg.EnvFile))
+		} else if info.IsDir() {
+			errs = append(errs, fmt.Sprintf("env_file %s is a directory", cfg.EnvFile))
+		}
+	}
+	for procName, proc := range cfg.Processes {
+		if proc.EnvFile == "" {
+			continue
+		}
+		if info, err := os.Stat(proc.EnvFile); err != nil {
+			errs = append(errs, fmt.Sprintf("process %q: env_file %s does not exist", procName, proc.EnvFile))
+		} else if info.IsDir() {
+			errs = append(errs, fmt.Sprintf("process %q: env_file %s is a directory", procName, proc.EnvFile))
+		}
+	}
+
+	// A working_dir that exists but is a file can never work. A missing one
+	// might still be created by the time the process starts (for example by
+	// a dependency), so that is only a warning.
+	for procName, proc := range cfg.Processes {
+		if proc.WorkingDir == "" {
+			continue
+		}
+		if info, err := os.Stat(proc.WorkingDir); err == nil && !info.IsDir() {
+			errs = append(errs, fmt.Sprintf("process %q: working_dir %s is not a directory", procName, proc.WorkingDir))
+		}
 	}
 
+	// Validate stop settings.
+	for procName, proc := range cfg.Processes {
+		if _, err := ParseSignal(proc.StopSignal); err != nil {
+			errs = append(errs, fmt.Sprintf("process %q: stop_signal: %s", procName, err))
+		}
+		if proc.StopTimeout.Duration() < 0 {
+			errs = append(errs, fmt.Sprintf("process %q: stop_timeout must not be negative", procName))
+		}
+		if proc.StartupDelayDuration() < 0 {
+			errs = append(errs, fmt.Sprintf("process %q: startup_delay must not be negative", procName))
+		}
+		for _, k := range proc.InheritEnv {
+			if k == "" || strings.Contains(k, "=") {
+				errs = append(errs, fmt.Sprintf("process %q: inherit_env: invalid variable name %q", procName, k))
+			}
+		}
+		if proc.StartTimeout != nil && proc.StartTimeout.Duration() <= 0 {
+			errs = append(errs, fmt.Sprintf("process %q: start_timeout must be positive", procName))
+		}
+	}
+
+	errs = append(errs, templateErrors(cfg)...)
+
+	// Validate file watches.
+	for procName, proc := range cfg.Processes {
+		w := proc.Watch
+		if len(w.Patterns) > 0 && !w.Configured() {
+			errs = append(errs, fmt.Sprintf("process %q: watch.patterns needs watch.paths", procName))
+		}
+		for _, pattern := range w.Patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Sprintf("process %q: watch pattern %q is invalid", procName, pattern))
+			}
+		}
+		if w.Debounce < 0 {
+			errs = append(errs, fmt.Sprintf("process %q: watch.debounce must not be negative", procName))
+		}
+	}
+
+	// Validate restart policies.
+	for procName, proc := range cfg.Processes {
+		if proc.LogBufferSize < 0 {
+			errs = append(errs, fmt.Sprintf("process %q: log_buffer_size must not be negative", procName))
+		}
+		if proc.LogMaxBytes < 0 {
+			errs = append(errs, fmt.Sprintf("process %q: log_buffer_bytes must not be negative", procName))
+		}
+		if proc.Nice < -20 || proc.Nice > 19 {
+			errs = append(errs, fmt.Sprintf("process %q: nice must be between -20 and 19 (got %d)", procName, proc.Nice))
+		}
+		if _, err := proc.Credential(); err != nil {
+			errs = append(errs, fmt.Sprintf("process %q: %s", procName, err))
+		}
+		if proc.LogMaxLine < 0 {
+			errs = append(errs, fmt.Sprintf("process %q: log_max_line must not be negative", procName))
+		}
+		if proc.Stdin != "" && proc.StdinFile != "" {
+			errs = append(errs, fmt.Sprintf("process %q: stdin and stdin_file are mutually exclusive", procName))
+		}
+		for _, code := range proc.SuccessExit {
+			if code < 0 || code > 255 {
+				errs = append(errs, fmt.Sprintf("process %q: success exit code %d must be between 0 and 255", procName, code))
+			}
+		}
+		if proc.Schedule != "" {
+			if _, err := ParseSchedule(proc.Schedule); err != nil {
+				errs = append(errs, fmt.Sprintf("process %q: %s", procName, err))
+			}
+			if proc.Restart == RestartAlways {
+				errs = append(errs, fmt.Sprintf("process %q: schedule and restart %s are mutually exclusive", procName, RestartAlways))
+			}
+		}
+		for _, dep := range proc.DependsOn {
+			if target, ok := cfg.Processes[dep.Name]; ok && target.Schedule != "" {
+				errs = append(errs, fmt.Sprintf("process %q: cannot depend on scheduled process %q", procName, dep.Name))
+			}
+		}
+		switch proc.Restart {
+		case "", RestartOnFailure, RestartAlways, RestartNever:
+		default:
+			errs = append(errs, fmt.Sprintf("process %q: restart must be one of %s, %s, %s (got %q)",
+				procName, RestartOnFailure, RestartAlways, RestartNever, proc.Restart))
+		}
+	}
+
+	// Validate health check definitions.
+	for procName, proc := range cfg.Processes {
+		hc := proc.HealthCheck
+		if !hc.Configured() {
+			continue
+		}
+		probes := 0
+		for _, v := range []string{hc.TCP, hc.HTTP, hc.Command} {
+			if v != "" {
+				probes++
+			}
+		}
+		if probes > 1 {
+			errs = append(errs, fmt.Sprintf("process %q: health_check must set only one of tcp, http, or command", procName))
+		}
+		if hc.HTTP != "" && !strings.HasPrefix(hc.HTTP, "http://") && !strings.HasPrefix(hc.HTTP, "https://") {
+			errs = append(errs, fmt.Sprintf("process %q: health_check.http must be an http:// or https:// URL", procName))
+		}
+		if hc.Interval.Duration() < 0 {
+			errs = append(errs, fmt.Sprintf("process %q: health_check.interval must not be negative", procName))
+		}
+		if hc.Timeout.Duration() < 0 {
+			errs = append(errs, fmt.Sprintf("process %q: health_check.timeout must not be negative", procName))
+		}
+	}
+
+	errs = append(errs, validateKeybindings(cfg.Keybindings)...)
+	errs = append(errs, validateTheme(cfg.Theme)...)
+	errs = append(errs, validateWebhooks(cfg.Webhooks)...)
+
 	// Detect dependency cycles.
 	if err := detectCycles(cfg); err != nil {
 		errs = append(errs, err.Error())
@@ -128,55 +373,177 @@ func Validate(cfg *Config) error {
 	return nil
 }
 
-// detectCycles uses Kahn's algorithm to detect cycles in the dependency graph.
-func detectCycles(cfg *Config) error {
-	// Build in-degree map.
-	inDegree := make(map[string]int)
-	dependents := make(map[string][]string) // dep -> processes that depend on it
+// Warnings reports config problems that don't prevent shepherd from running:
+// processes that no group lists and no other process depends on, and
+// working directories that don't exist yet. Unreferenced processes only
+// appear under "other" in the TUI and are never started by a stack or group,
+// which usually means forgotten wiring.
+func Warnings(cfg *Config) []string {
+	referenced := make(map[string]bool)
+	for _, group := range cfg.Groups {
+		for _, p := range group.Processes {
+			referenced[p] = true
+		}
+	}
+	for _, proc := range cfg.Processes {
+		for _, dep := range proc.DependencyNames() {
+			referenced[dep] = true
+		}
+	}
 
+	var warnings []string
 	for name := range cfg.Processes {
-		inDegree[name] = 0
+		if !referenced[name] {
+			warnings = append(warnings, fmt.Sprintf("process %q is not in any group or stack", name))
+		}
 	}
 	for name, proc := range cfg.Processes {
-		for _, dep := range proc.DependsOn {
-			if _, ok := cfg.Processes[dep]; ok {
-				inDegree[name]++
-				dependents[dep] = append(dependents[dep], name)
-			}
+		if proc.WorkingDir == "" {
+			continue
+		}
+		if _, err := os.Stat(proc.WorkingDir); os.IsNotExist(err) {
+			warnings = append(warnings, fmt.Sprintf("process %q: working_dir %s does not exist", name, proc.WorkingDir))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// duplicates returns the names that appear more than once in names, in order
+// of their second appearance.
+func duplicates(names []string) []string {
+	seen := make(map[string]int, len(names))
+	var dups []string
+	for _, n := range names {
+		seen[n]++
+		if seen[n] == 2 {
+			dups = append(dups, n)
 		}
 	}
+	return dups
+}
 
-	// Start with all nodes that have no dependencies.
-	var queue []string
-	for name, degree := range inDegree {
-		if degree == 0 {
-			queue = append(queue, name)
+// detectCycles reports one dependency cycle, if any, as a path such as
+// "a -> b -> a". Group dependencies are checked first; once they are acyclic,
+// they take part in the process check, since every process in a group starts
+// after every process in the groups it depends on. Dependencies on unknown
+// processes or groups are ignored here; they are reported separately.
+func detectCycles(cfg *Config) error {
+	groupDeps := make(map[string][]string, len(cfg.Groups))
+	for name, group := range cfg.Groups {
+		for _, dep := range group.DependsOn {
+			if _, ok := cfg.Groups[dep]; ok {
+				groupDeps[name] = append(groupDeps[name], dep)
+			}
 		}
 	}
+	if cycle := FindCycle(groupDeps); cycle != nil {
+		return fmt.Errorf("group dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
 
-	visited := 0
-	for len(queue) > 0 {
-		node := queue[0]
-		queue = queue[1:]
-		visited++
+	deps := make(map[string][]string, len(cfg.Processes))
+	for name, proc := range cfg.Processes {
+		for _, dep := range proc.DependencyNames() {
+			if _, ok := cfg.Processes[dep]; ok {
+				deps[name] = append(deps[name], dep)
+			}
+		}
+	}
+	for name, after := range GroupOrdering(cfg) {
+		deps[name] = append(deps[name], after...)
+	}
+	if cycle := FindCycle(deps); cycle != nil {
+		return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
 
-		for _, dep := range dependents[node] {
-			inDegree[dep]--
-			if inDegree[dep] == 0 {
-				queue = append(queue, dep)
+// GroupOrdering expands group depends_on into process ordering: it maps each
+// process to the processes it must start after because its group depends on
+// theirs. A process in both groups is not ordered after itself. Unknown
+// groups and processes are skipped.
+func GroupOrdering(cfg *Config) map[string][]string {
+	seen := make(map[string]map[string]bool)
+	after := make(map[string][]string)
+	for _, group := range cfg.Groups {
+		for _, depName := range group.DependsOn {
+			dep, ok := cfg.Groups[depName]
+			if !ok {
+				continue
+			}
+			for _, p := range group.Processes {
+				if _, ok := cfg.Processes[p]; !ok {
+					continue
+				}
+				for _, q := range dep.Processes {
+					if _, ok := cfg.Processes[q]; !ok || q == p || seen[p][q] {
+						continue
+					}
+					if seen[p] == nil {
+						seen[p] = make(map[string]bool)
+					}
+					seen[p][q] = true
+					after[p] = append(after[p], q)
+				}
 			}
 		}
 	}
+	for _, procs := range after {
+		sort.Strings(procs)
+	}
+	return after
+}
 
-	if visited != len(cfg.Processes) {
-		// Find the processes involved in cycles.
-		var cycleNodes []string
-		for name, degree := range inDegree {
-			if degree > 0 {
-				cycleNodes = append(cycleNodes, name)
+// FindCycle returns one cycle in the graph where deps maps each node to the
+// nodes it depends on, as a path that starts and ends with the same node.
+// It returns nil if the graph is acyclic. Nodes are visited in name order, so
+// the same graph always yields the same cycle.
+func FindCycle(deps map[string][]string) []string {
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[string]int, len(deps))
+	var path []string
+
+	var visit func(node string) []string
+	visit = func(node string) []string {
+		state[node] = onStack
+		path = append(path, node)
+		for _, dep := range deps[node] {
+			switch state[dep] {
+			case onStack:
+				// A back edge: the cycle is the stack from dep onwards.
+				for i, n := range path {
+					if n == dep {
+						cycle := append([]string(nil), path[i:]...)
+						return append(cycle, dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
 			}
 		}
-		return fmt.Errorf("dependency cycle detected involving: %s", strings.Join(cycleNodes, ", "))
+		path = path[:len(path)-1]
+		state[node] = done
+		return nil
+	}
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if state[name] != unvisited {
+			continue
+		}
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
 	}
 	return nil
 }
@@ -219,6 +586,8 @@ processes:
     description: "Database tunnel through bastion"
     command: "ssh -N -L 5432:db.internal:5432 -p 2222 localhost"
     depends_on: [bastion]
+    health_check:
+      tcp: localhost:5432
     retry:
       enabled: true
       max_attempts: 3
@@ -238,9 +607,20 @@ func applyDefaults(cfg *Config) {
 	if cfg.Processes == nil {
 		cfg.Processes = make(map[string]Process)
 	}
+	if cfg.Control.Socket == "" {
+		cfg.Control.Socket = DefaultSocketPath()
+	}
+	if cfg.Session.File == "" {
+		cfg.Session.File = DefaultSessionPath()
+	}
 
+	// Processes inherit defaults.retry, which in turn falls back to
+	// DefaultRetryConfig for anything it leaves out.
 	defaults := DefaultRetryConfig()
+	retryBase := cfg.Defaults.Retry
+	healthDefaults := DefaultHealthCheck()
 	for name, proc := range cfg.Processes {
+		proc.Retry = proc.Retry.inherit(retryBase)
 		if proc.Retry.MaxAttempts == 0 && !proc.Retry.Enabled {
 			proc.Retry.MaxAttempts = defaults.MaxAttempts
 		}
@@ -253,6 +633,23 @@ func applyDefaults(cfg *Config) {
 		if proc.Retry.BackoffMultiplier == 0 {
 			proc.Retry.BackoffMultiplier = defaults.BackoffMultiplier
 		}
+		if proc.Restart == "" {
+			proc.Restart = RestartOnFailure
+		}
+		if proc.Shell == "" {
+			proc.Shell = cfg.Shell
+		}
+		if proc.Shell == "" {
+			proc.Shell = DefaultShell
+		}
+		if proc.HealthCheck.Configured() {
+			if proc.HealthCheck.Interval == 0 {
+				proc.HealthCheck.Interval = healthDefaults.Interval
+			}
+			if proc.HealthCheck.Timeout == 0 {
+				proc.HealthCheck.Timeout = healthDefaults.Timeout
+			}
+		}
 		cfg.Processes[name] = proc
 	}
 }
@@ -263,9 +660,27 @@ func expandPaths(cfg *Config) {
 		return
 	}
 
+	cfg.Control.Socket = os.ExpandEnv(expandTilde(cfg.Control.Socket, home))
+	cfg.Session.File = os.ExpandEnv(expandTilde(cfg.Session.File, home))
+	cfg.EnvFile = os.ExpandEnv(expandTilde(cfg.EnvFile, home))
+	for k, v := range cfg.Env {
+		cfg.Env[k] = os.ExpandEnv(expandTilde(v, home))
+	}
+
 	for name, proc := range cfg.Processes {
 		proc.WorkingDir = expandTilde(proc.WorkingDir, home)
 		proc.WorkingDir = os.ExpandEnv(proc.WorkingDir)
+		proc.LogFile = expandTilde(proc.LogFile, home)
+		proc.LogFile = os.ExpandEnv(proc.LogFile)
+		proc.EnvFile = expandTilde(proc.EnvFile, home)
+		proc.EnvFile = os.ExpandEnv(proc.EnvFile)
+		proc.StdinFile = expandTilde(proc.StdinFile, home)
+		proc.StdinFile = os.ExpandEnv(proc.StdinFile)
+		proc.Shell = expandTilde(proc.Shell, home)
+		proc.Shell = os.ExpandEnv(proc.Shell)
+		for i, path := range proc.Watch.Paths {
+			proc.Watch.Paths[i] = os.ExpandEnv(expandTilde(path, home))
+		}
 
 		for k, v := range proc.Env {
 			proc.Env[k] = expandTilde(v, home)
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
 	"time"
 
@@ -28,7 +29,7 @@ func TestLoad_ValidConfig(t *testing.T) {
 	assert.Equal(t, 2.0, bastion.Retry.BackoffMultiplier)
 
 	forward := cfg.Processes["staging-forward"]
-	assert.Equal(t, []string{"bastion"}, forward.DependsOn)
+	assert.Equal(t, []string{"bastion"}, forward.DependencyNames())
 }
 
 func TestLoad_FileNotFound(t *testing.T) {
@@ -104,7 +105,7 @@ func TestValidate_DuplicateNames(t *testing.T) {
 func TestValidate_SelfDependency(t *testing.T) {
 	cfg := &Config{
 		Processes: map[string]Process{
-			"a": {Command: "echo a", DependsOn: []string{"a"}},
+			"a": {Command: "echo a", DependsOn: []Dependency{{Name: "a"}}},
 		},
 	}
 	applyDefaults(cfg)
@@ -117,7 +118,7 @@ func TestValidate_SelfDependency(t *testing.T) {
 func TestValidate_UndefinedDependency(t *testing.T) {
 	cfg := &Config{
 		Processes: map[string]Process{
-			"a": {Command: "echo a", DependsOn: []string{"nonexistent"}},
+			"a": {Command: "echo a", DependsOn: []Dependency{{Name: "nonexistent"}}},
 		},
 	}
 	applyDefaults(cfg)
@@ -130,16 +131,116 @@ func TestValidate_UndefinedDependency(t *testing.T) {
 func TestValidate_CyclicDependency(t *testing.T) {
 	cfg := &Config{
 		Processes: map[string]Process{
-			"a": {Command: "echo a", DependsOn: []string{"c"}},
-			"b": {Command: "echo b", DependsOn: []string{"a"}},
-			"c": {Command: "echo c", DependsOn: []string{"b"}},
+			"a": {Command: "echo a", DependsOn: []Dependency{{Name: "c"}}},
+			"b": {Command: "echo b", DependsOn: []Dependency{{Name: "a"}}},
+			"c": {Command: "echo c", DependsOn: []Dependency{{Name: "b"}}},
 		},
 	}
 	applyDefaults(cfg)
 
 	err := Validate(cfg)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "dependency cycle detected")
+	assert.Contains(t, err.Error(), "dependency cycle detected: a -> c -> b -> a")
+}
+
+func TestFindCycle(t *testing.T) {
+	assert.Nil(t, FindCycle(map[string][]string{
+		"api": {"db", "cache"},
+		"web": {"api", "db"},
+	}))
+	assert.Equal(t, []string{"x", "x"}, FindCycle(map[string][]string{"x": {"x"}}))
+	// Only the nodes on the cycle are reported, not the ones leading into it.
+	assert.Equal(t, []string{"b", "c", "d", "b"}, FindCycle(map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"d"},
+		"d": {"b"},
+	}))
+}
+
+func TestValidate_GroupDependencies(t *testing.T) {
+	cfg := &Config{
+		Groups: map[string]Group{
+			"infra":   {Processes: []string{"db"}},
+			"backend": {Processes: []string{"api"}, DependsOn: []string{"infra", "missing", "infra"}},
+			"self":    {Processes: []string{"api"}, DependsOn: []string{"self"}},
+		},
+		Processes: map[string]Process{
+			"db":  {Command: "true"},
+			"api": {Command: "true"},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `group "backend" depends on undefined group "missing"`)
+	assert.Contains(t, err.Error(), `group "backend" lists dependency "infra" more than once`)
+	assert.Contains(t, err.Error(), `group "self" depends on itself`)
+}
+
+func TestValidate_GroupDependencyCycle(t *testing.T) {
+	cfg := &Config{
+		Groups: map[string]Group{
+			"a": {Processes: []string{"x"}, DependsOn: []string{"b"}},
+			"b": {Processes: []string{"y"}, DependsOn: []string{"a"}},
+		},
+		Processes: map[string]Process{
+			"x": {Command: "true"},
+			"y": {Command: "true"},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "group dependency cycle detected: a -> b -> a")
+
+	// A process dependency against the group order is a cycle too.
+	cfg.Groups["b"] = Group{Processes: []string{"y"}}
+	cfg.Processes["y"] = Process{Command: "true", DependsOn: []Dependency{{Name: "x"}}}
+	err = Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle detected: x -> y -> x")
+}
+
+func TestGroupOrdering(t *testing.T) {
+	cfg := &Config{
+		Groups: map[string]Group{
+			"infra":   {Processes: []string{"db", "cache"}},
+			"backend": {Processes: []string{"api", "db"}, DependsOn: []string{"infra"}},
+		},
+		Processes: map[string]Process{
+			"db":    {Command: "true"},
+			"cache": {Command: "true"},
+			"api":   {Command: "true"},
+		},
+	}
+	assert.Equal(t, map[string][]string{
+		"api": {"cache", "db"},
+		"db":  {"cache"},
+	}, GroupOrdering(cfg))
+}
+
+func TestValidate_Tags(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"api": {Command: "true", Tags: []string{"backend", "backend"}},
+			"web": {Command: "true", Tags: []string{"front end"}},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "api" lists tag "backend" more than once`)
+	assert.Contains(t, err.Error(), `process "web": tag "front end" must be non-empty and contain no spaces`)
+}
+
+func TestConfig_TaggedProcesses(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"worker": {Command: "true", Tags: []string{"backend"}},
+			"api":    {Command: "true", Tags: []string{"backend", "critical"}},
+			"web":    {Command: "true", Tags: []string{"frontend"}},
+		},
+	}
+	assert.Equal(t, []string{"api", "worker"}, cfg.TaggedProcesses("backend"))
+	assert.Empty(t, cfg.TaggedProcesses("missing"))
 }
 
 func TestValidate_MissingCommand(t *testing.T) {
@@ -250,3 +351,710 @@ func TestApplyDefaults(t *testing.T) {
 	assert.Equal(t, defaults.MaxBackoff, proc.Retry.MaxBackoff)
 	assert.Equal(t, defaults.BackoffMultiplier, proc.Retry.BackoffMultiplier)
 }
+
+func TestValidate_HealthCheckMultipleProbes(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"a": {
+				Command: "echo a",
+				HealthCheck: HealthCheck{
+					TCP:     "localhost:5432",
+					Command: "true",
+				},
+			},
+		},
+	}
+	applyDefaults(cfg)
+
+	err := Validate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "only one of tcp, http, or command")
+}
+
+func TestApplyDefaults_HealthCheck(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"a": {Command: "echo a", HealthCheck: HealthCheck{TCP: "localhost:5432"}},
+			"b": {Command: "echo b"},
+		},
+	}
+	applyDefaults(cfg)
+
+	defaults := DefaultHealthCheck()
+	assert.Equal(t, defaults.Interval, cfg.Processes["a"].HealthCheck.Interval)
+	assert.Equal(t, defaults.Timeout, cfg.Processes["a"].HealthCheck.Timeout)
+	assert.False(t, cfg.Processes["b"].HealthCheck.Configured())
+}
+
+func TestParseSignal(t *testing.T) {
+	sig, err := ParseSignal("")
+	require.NoError(t, err)
+	assert.Equal(t, syscall.SIGTERM, sig)
+
+	sig, err = ParseSignal("SIGQUIT")
+	require.NoError(t, err)
+	assert.Equal(t, syscall.SIGQUIT, sig)
+
+	sig, err = ParseSignal("int")
+	require.NoError(t, err)
+	assert.Equal(t, syscall.SIGINT, sig)
+
+	_, err = ParseSignal("SIGBOGUS")
+	assert.Error(t, err)
+}
+
+func TestValidate_UnknownStopSignal(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"a": {Command: "echo a", StopSignal: "SIGBOGUS"},
+		},
+	}
+	applyDefaults(cfg)
+
+	err := Validate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `process "a": stop_signal: unknown signal "SIGBOGUS"`)
+}
+
+func TestValidate_NegativeStopTimeout(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"a": {Command: "echo a", StopTimeout: Duration(-time.Second)},
+		},
+	}
+	applyDefaults(cfg)
+
+	err := Validate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "stop_timeout must not be negative")
+}
+
+func TestValidate_InvalidRestartPolicy(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"a": {Command: "echo a", Restart: "sometimes"},
+		},
+	}
+	applyDefaults(cfg)
+
+	err := Validate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `restart must be one of on-failure, always, never (got "sometimes")`)
+}
+
+func TestApplyDefaults_RestartPolicy(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"a": {Command: "echo a"},
+			"b": {Command: "echo b", Restart: RestartAlways},
+		},
+	}
+	applyDefaults(cfg)
+
+	assert.Equal(t, RestartOnFailure, cfg.Processes["a"].Restart)
+	assert.Equal(t, RestartAlways, cfg.Processes["b"].Restart)
+}
+
+func TestLoad_CommandList(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+processes:
+  shell:
+    command: "echo 'hello world'"
+  direct:
+    command: ["ssh", "-N", "-L", "5432:db:5432", "host"]
+    depends_on: [shell]
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, Validate(cfg))
+
+	shell := cfg.Processes["shell"]
+	assert.Equal(t, "echo 'hello world'", shell.Command)
+	assert.Empty(t, shell.Args)
+
+	direct := cfg.Processes["direct"]
+	assert.Empty(t, direct.Command)
+	assert.Equal(t, []string{"ssh", "-N", "-L", "5432:db:5432", "host"}, direct.Args)
+	assert.Equal(t, []string{"shell"}, direct.DependencyNames())
+	assert.Equal(t, "ssh -N -L 5432:db:5432 host", direct.CommandLine())
+}
+
+func TestLoad_ExpandsLogFile(t *testing.T) {
+	t.Setenv("SHEPHERD_LOG_DIR", "/var/log/shepherd")
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+processes:
+  a:
+    command: "echo a"
+    log_file: "$SHEPHERD_LOG_DIR/a.log"
+  b:
+    command: "echo b"
+    log_file: "~/logs/b.log"
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	home, _ := os.UserHomeDir()
+	assert.Equal(t, "/var/log/shepherd/a.log", cfg.Processes["a"].LogFile)
+	assert.Equal(t, filepath.Join(home, "logs", "b.log"), cfg.Processes["b"].LogFile)
+}
+
+func TestLoad_StartupDelay(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+processes:
+  default:
+    command: "echo a"
+  slow:
+    command: "echo b"
+    startup_delay: 10s
+  instant:
+    command: "echo c"
+    startup_delay: 0s
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultStartupDelay, cfg.Processes["default"].StartupDelayDuration())
+	assert.Equal(t, 10*time.Second, cfg.Processes["slow"].StartupDelayDuration())
+	assert.Equal(t, time.Duration(0), cfg.Processes["instant"].StartupDelayDuration())
+}
+
+func TestLoad_Shell(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+shell: /bin/bash
+processes:
+  inherited:
+    command: "echo a"
+  own:
+    command: "echo b"
+    shell: /usr/bin/zsh
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "/bin/bash", cfg.Processes["inherited"].Shell)
+	assert.Equal(t, "/usr/bin/zsh", cfg.Processes["own"].Shell)
+
+	os.WriteFile(path, []byte(`version: 1
+processes:
+  plain:
+    command: "echo a"
+`), 0644)
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultShell, cfg.Processes["plain"].Shell)
+}
+
+func TestLoad_DefaultRetry(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+defaults:
+  retry:
+    enabled: true
+    max_attempts: 10
+    initial_backoff: 1s
+processes:
+  inherited:
+    command: "echo a"
+  partial:
+    command: "echo b"
+    retry:
+      max_attempts: 0
+      max_backoff: 5s
+  disabled:
+    command: "echo c"
+    retry:
+      enabled: false
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	inherited := cfg.Processes["inherited"].Retry
+	assert.True(t, inherited.Enabled)
+	assert.Equal(t, 10, inherited.MaxAttempts)
+	assert.Equal(t, Duration(time.Second), inherited.InitialBackoff)
+	assert.Equal(t, DefaultRetryConfig().MaxBackoff, inherited.MaxBackoff)
+
+	partial := cfg.Processes["partial"].Retry
+	assert.True(t, partial.Enabled)
+	assert.Equal(t, 0, partial.MaxAttempts, "explicit 0 (unlimited) wins over the default")
+	assert.Equal(t, Duration(5*time.Second), partial.MaxBackoff)
+	assert.Equal(t, Duration(time.Second), partial.InitialBackoff)
+
+	assert.False(t, cfg.Processes["disabled"].Retry.Enabled)
+}
+
+func TestLoad_DependencyForms(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+processes:
+  db:
+    command: "sleep 1"
+  cache:
+    command: "sleep 1"
+  app:
+    command: "sleep 1"
+    depends_on:
+      - db
+      - name: cache
+        optional: true
+        condition: started
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, Validate(cfg))
+
+	app := cfg.Processes["app"]
+	assert.Equal(t, []Dependency{
+		{Name: "db"},
+		{Name: "cache", Optional: true, Condition: DependencyStarted},
+	}, app.DependsOn)
+	assert.Equal(t, []string{"db", "cache"}, app.DependencyNames())
+}
+
+func TestValidate_InvalidDependencyCondition(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"db":  {Command: "sleep 1"},
+			"app": {Command: "sleep 1", DependsOn: []Dependency{{Name: "db", Condition: "ready"}}},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "condition")
+}
+
+func TestProcess_IsSuccessExit(t *testing.T) {
+	assert.True(t, Process{}.IsSuccessExit(0))
+	assert.False(t, Process{}.IsSuccessExit(2))
+
+	p := Process{SuccessExit: []int{0, 2}}
+	assert.True(t, p.IsSuccessExit(2))
+	assert.False(t, p.IsSuccessExit(1))
+}
+
+func TestValidate_InvalidSuccessExitCode(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"job": {Command: "true", SuccessExit: []int{0, 300}},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "success exit code 300")
+}
+
+func TestLoad_Keybindings(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+keybindings:
+  stop: d
+  quit: [q, ctrl+q]
+processes:
+  app:
+    command: "sleep 1"
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, Validate(cfg))
+
+	assert.Equal(t, KeyList{"d"}, cfg.Keybindings["stop"])
+	assert.Equal(t, KeyList{"q", "ctrl+q"}, cfg.Keybindings["quit"])
+}
+
+func TestValidate_InvalidKeybindings(t *testing.T) {
+	cfg := &Config{
+		Keybindings: map[string]KeyList{
+			"stop":    {"dd"},
+			"restart": {"x"},
+			"start":   {"x"},
+			"launch":  {"l"},
+		},
+		Processes: map[string]Process{"app": {Command: "true"}},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid key "dd"`)
+	assert.Contains(t, err.Error(), `key "x" is bound to both`)
+	assert.Contains(t, err.Error(), `unknown action "launch"`)
+}
+
+func TestValidate_KeybindingsMergedWithDefaults(t *testing.T) {
+	cfg := &Config{
+		Keybindings: map[string]KeyList{"stop": {"r"}},
+		Processes:   map[string]Process{"app": {Command: "true"}},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `key "r" is bound to both "stop" and "restart" (default) in the process list`)
+
+	// Panels may share keys: t toggles timestamps only in the log panel.
+	cfg.Keybindings = map[string]KeyList{"start_group": {"t"}, "top": {"s"}}
+	require.NoError(t, Validate(cfg))
+}
+
+func TestKeyActions_HaveDefaultsAndPanels(t *testing.T) {
+	inPanel := make(map[string]bool)
+	for _, actions := range keyPanels {
+		for _, a := range actions {
+			inPanel[a] = true
+		}
+	}
+	for _, a := range KeyActions {
+		assert.NotEmpty(t, DefaultKeys[a], "%s has no default keys", a)
+		assert.True(t, inPanel[a], "%s is in no panel", a)
+	}
+	assert.Len(t, DefaultKeys, len(KeyActions))
+}
+
+func TestValidate_Theme(t *testing.T) {
+	cfg := &Config{
+		Theme: map[string]string{
+			"running": "#0077bb",
+			"failed":  "208",
+			"accent":  "#abc",
+		},
+		Processes: map[string]Process{"app": {Command: "true"}},
+	}
+	require.NoError(t, Validate(cfg))
+
+	cfg.Theme = map[string]string{
+		"failed":  "red",
+		"warning": "#ffffff",
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed color "red"`)
+	assert.Contains(t, err.Error(), `unknown color "warning"`)
+}
+
+func TestValidate_UnsupportedVersion(t *testing.T) {
+	cfg := &Config{
+		Version:   2,
+		Processes: map[string]Process{"app": {Command: "true"}},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported config version 2, this build supports version 1")
+
+	cfg.Version = CurrentVersion
+	assert.NoError(t, Validate(cfg))
+}
+
+func TestValidate_DuplicateEntries(t *testing.T) {
+	cfg := &Config{
+		Stacks: map[string]Stack{
+			"dev": {Groups: []string{"tunnels", "tunnels"}},
+		},
+		Groups: map[string]Group{
+			"tunnels": {Processes: []string{"db", "app", "db"}},
+		},
+		Processes: map[string]Process{
+			"db":  {Command: "true"},
+			"app": {Command: "true", DependsOn: []Dependency{{Name: "db"}, {Name: "db", Optional: true}}},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `stack "dev" lists group "tunnels" more than once`)
+	assert.Contains(t, err.Error(), `group "tunnels" lists process "db" more than once`)
+	assert.Contains(t, err.Error(), `process "app" lists dependency "db" more than once`)
+}
+
+func TestWarnings_OrphanProcesses(t *testing.T) {
+	cfg := &Config{
+		Groups: map[string]Group{
+			"web": {Processes: []string{"app"}},
+		},
+		Processes: map[string]Process{
+			"app":    {Command: "true", DependsOn: []Dependency{{Name: "db"}}},
+			"db":     {Command: "true"},
+			"orphan": {Command: "true"},
+		},
+	}
+	assert.Equal(t, []string{`process "orphan" is not in any group or stack`}, Warnings(cfg))
+}
+
+func TestValidate_Nice(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"ok":   {Command: "true", Nice: 10},
+			"low":  {Command: "true", Nice: 20},
+			"high": {Command: "true", Nice: -21},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "low": nice must be between -20 and 19 (got 20)`)
+	assert.Contains(t, err.Error(), `process "high": nice must be between -20 and 19 (got -21)`)
+	assert.NotContains(t, err.Error(), `"ok"`)
+}
+
+func TestValidate_UnknownUser(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"api": {Command: "true", User: "no-such-user-shepherd"},
+			"web": {Command: "true", Group: "no-such-group-shepherd"},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "api": unknown user "no-such-user-shepherd"`)
+	assert.Contains(t, err.Error(), `process "web": unknown group "no-such-group-shepherd"`)
+}
+
+func TestProcess_Credential(t *testing.T) {
+	cred, err := Process{}.Credential()
+	require.NoError(t, err)
+	assert.Nil(t, cred, "no user or group means no credential")
+
+	cred, err = Process{User: "0"}.Credential()
+	require.NoError(t, err)
+	assert.Equal(t, &syscall.Credential{Uid: 0, Gid: 0}, cred)
+
+	cred, err = Process{User: "root", Group: "0"}.Credential()
+	require.NoError(t, err)
+	assert.Equal(t, &syscall.Credential{Uid: 0, Gid: 0}, cred)
+}
+
+func TestValidate_Watch(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"ok":       {Command: "true", Watch: WatchConfig{Paths: []string{"."}, Patterns: []string{"*.go"}}},
+			"nopaths":  {Command: "true", Watch: WatchConfig{Patterns: []string{"*.go"}}},
+			"badglob":  {Command: "true", Watch: WatchConfig{Paths: []string{"."}, Patterns: []string{"[a"}}},
+			"debounce": {Command: "true", Watch: WatchConfig{Paths: []string{"."}, Debounce: Duration(-time.Second)}},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "nopaths": watch.patterns needs watch.paths`)
+	assert.Contains(t, err.Error(), `process "badglob": watch pattern "[a" is invalid`)
+	assert.Contains(t, err.Error(), `process "debounce": watch.debounce must not be negative`)
+	assert.NotContains(t, err.Error(), `"ok"`)
+}
+
+func TestWatchConfig_Matches(t *testing.T) {
+	assert.True(t, WatchConfig{}.Matches("/src/main.go"), "no patterns matches everything")
+	w := WatchConfig{Patterns: []string{"*.go", "go.mod"}}
+	assert.True(t, w.Matches("/src/main.go"))
+	assert.True(t, w.Matches("/src/go.mod"))
+	assert.False(t, w.Matches("/src/main.go~"))
+}
+
+func TestValidate_Schedule(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"backup":  {Command: "true", Schedule: "0 3 * * *"},
+			"bad":     {Command: "true", Schedule: "every day"},
+			"looping": {Command: "true", Schedule: "@hourly", Restart: RestartAlways},
+			"api":     {Command: "true", DependsOn: []Dependency{{Name: "backup"}}},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "bad": invalid schedule "every day"`)
+	assert.Contains(t, err.Error(), `process "looping": schedule and restart always are mutually exclusive`)
+	assert.Contains(t, err.Error(), `process "api": cannot depend on scheduled process "backup"`)
+}
+
+func TestValidate_WorkingDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0644))
+	missing := filepath.Join(tmpDir, "missing")
+
+	cfg := &Config{
+		Groups: map[string]Group{"g": {Processes: []string{"dir", "file", "missing"}}},
+		Processes: map[string]Process{
+			"dir":     {Command: "true", WorkingDir: tmpDir},
+			"file":    {Command: "true", WorkingDir: file},
+			"missing": {Command: "true", WorkingDir: missing},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "file": working_dir `+file+" is not a directory")
+	assert.NotContains(t, err.Error(), `"missing"`)
+
+	assert.Equal(t, []string{`process "missing": working_dir ` + missing + " does not exist"}, Warnings(cfg))
+}
+
+func TestDiffConfigs(t *testing.T) {
+	old := &Config{
+		Processes: map[string]Process{
+			"same":    {Command: "true", Env: map[string]string{"A": "1"}},
+			"command": {Command: "true"},
+			"env":     {Command: "true", Env: map[string]string{"A": "1"}},
+			"desc":    {Command: "true", Description: "old"},
+			"confirm": {Command: "true"},
+			"removed": {Command: "true"},
+		},
+	}
+	new := &Config{
+		Processes: map[string]Process{
+			"same":    {Command: "true", Env: map[string]string{"A": "1"}},
+			"command": {Command: "false"},
+			"env":     {Command: "true", Env: map[string]string{"A": "2"}},
+			"desc":    {Command: "true", Description: "new"},
+			"confirm": {Command: "true", ConfirmStop: true},
+			"added":   {Command: "true"},
+		},
+	}
+	assert.Equal(t, []string{"command", "env"}, DiffConfigs(old, new))
+}
+
+func TestValidate_Webhooks(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{"app": {Command: "true"}},
+		Webhooks: []Webhook{
+			{URL: "https://hooks.example.com/x", Events: []string{"failed", "recovered"}},
+			{URL: "hooks.example.com", Events: []string{"crashed"}, Timeout: Duration(-time.Second)},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "webhooks[0]")
+	assert.Contains(t, err.Error(), `webhooks[1]: url "hooks.example.com" must be an http or https URL`)
+	assert.Contains(t, err.Error(), `webhooks[1]: unknown event "crashed"`)
+	assert.Contains(t, err.Error(), "webhooks[1]: timeout must not be negative")
+
+	assert.True(t, Webhook{}.Fires("failed"))
+	assert.False(t, Webhook{}.Fires("stopped"))
+}
+
+func TestValidate_NegativeStartLimit(t *testing.T) {
+	cfg := &Config{StartLimit: -1, Processes: map[string]Process{"app": {Command: "true"}}}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_concurrent_starts must not be negative")
+}
+
+func TestValidate_InheritEnv(t *testing.T) {
+	cfg := &Config{Processes: map[string]Process{
+		"app": {Command: "true", InheritEnv: []string{"PATH", "", "A=B"}},
+	}}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "app": inherit_env: invalid variable name ""`)
+	assert.Contains(t, err.Error(), `process "app": inherit_env: invalid variable name "A=B"`)
+	assert.NotContains(t, err.Error(), `"PATH"`)
+}
+
+func TestValidate_StartTimeout(t *testing.T) {
+	zero := Duration(0)
+	cfg := &Config{Processes: map[string]Process{"app": {Command: "true", StartTimeout: &zero}}}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "app": start_timeout must be positive`)
+}
+
+func TestConfig_StartTimeout(t *testing.T) {
+	thirty := Duration(30 * time.Second)
+	cfg := &Config{Processes: map[string]Process{
+		"app": {Command: "true"},
+		"db":  {Command: "true", StartTimeout: &thirty},
+	}}
+	assert.Equal(t, DefaultStartTimeout, cfg.StartTimeout("app"))
+	assert.Equal(t, DefaultHealthPollInterval, cfg.HealthPollInterval())
+
+	cfg.HealthTimeout = Duration(5 * time.Minute)
+	cfg.HealthPoll = Duration(50 * time.Millisecond)
+	assert.Equal(t, 5*time.Minute, cfg.StartTimeout("app"))
+	assert.Equal(t, 30*time.Second, cfg.StartTimeout("db"))
+	assert.Equal(t, 50*time.Millisecond, cfg.HealthPollInterval())
+
+	cfg.HealthTimeout = Duration(-time.Second)
+	cfg.HealthPoll = Duration(-time.Second)
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "health_timeout must not be negative")
+	assert.Contains(t, err.Error(), "health_poll_interval must not be negative")
+}
+
+func TestValidate_LogTimestampFormat(t *testing.T) {
+	cfg := &Config{LogTimestamp: "hh:mm", Processes: map[string]Process{"app": {Command: "true"}}}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid log_timestamp_format "hh:mm"`)
+
+	cfg.LogTimestamp = "RFC3339"
+	assert.NoError(t, Validate(cfg))
+}
+
+func TestParseTimestampFormat(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"time_ms", "15:04:05.000"},
+		{"datetime", "2006-01-02 15:04:05"},
+		{"rfc3339", time.RFC3339},
+		{"Jan 2 15:04", "Jan 2 15:04"},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestampFormat(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestValidate_RetryJitter(t *testing.T) {
+	for _, jitter := range []float64{-0.1, 1} {
+		j := jitter
+		cfg := &Config{Processes: map[string]Process{
+			"app": {Command: "true", Retry: RetryConfig{
+				Enabled:           true,
+				InitialBackoff:    Duration(time.Second),
+				MaxBackoff:        Duration(time.Second),
+				BackoffMultiplier: 2,
+				Jitter:            &j,
+			}},
+		}}
+		err := Validate(cfg)
+		require.Error(t, err, "jitter %v", jitter)
+		assert.Contains(t, err.Error(), "jitter must be at least 0 and less than 1")
+	}
+
+	zero := 0.0
+	cfg := &Config{Processes: map[string]Process{
+		"app": {Command: "true", Retry: RetryConfig{
+			Enabled:           true,
+			InitialBackoff:    Duration(time.Second),
+			MaxBackoff:        Duration(time.Second),
+			BackoffMultiplier: 2,
+			Jitter:            &zero,
+		}},
+	}}
+	assert.NoError(t, Validate(cfg))
+}
+
+func TestValidate_RetryMode(t *testing.T) {
+	cfg := &Config{Processes: map[string]Process{
+		"fixed": {Command: "true", Retry: RetryConfig{
+			Enabled:        true,
+			Mode:           RetryFixed,
+			InitialBackoff: Duration(5 * time.Second),
+		}},
+	}}
+	assert.NoError(t, Validate(cfg), "fixed mode ignores max_backoff and backoff_multiplier")
+
+	cfg.Processes["bogus"] = Process{Command: "true", Retry: RetryConfig{Mode: "linear"}}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "bogus": retry mode must be exponential or fixed (got "linear")`)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// Credential resolves the process's user and group to numeric IDs. It returns
+// nil when neither is set, so the process runs as shepherd's own user. A
+// group alone keeps the current user; a user alone uses their primary group.
+// Either may be given as a name or a numeric ID.
+func (p Process) Credential() (*syscall.Credential, error) {
+	if p.User == "" && p.Group == "" {
+		return nil, nil
+	}
+
+	uid, gid := os.Getuid(), os.Getgid()
+	if p.User != "" {
+		u, err := lookupUser(p.User)
+		if err != nil {
+			return nil, err
+		}
+		if uid, err = strconv.Atoi(u.Uid); err != nil {
+			return nil, fmt.Errorf("user %q has non-numeric uid %q", p.User, u.Uid)
+		}
+		if gid, err = strconv.Atoi(u.Gid); err != nil {
+			return nil, fmt.Errorf("user %q has non-numeric gid %q", p.User, u.Gid)
+		}
+	}
+	if p.Group != "" {
+		g, err := lookupGroup(p.Group)
+		if err != nil {
+			return nil, err
+		}
+		if gid, err = strconv.Atoi(g.Gid); err != nil {
+			return nil, fmt.Errorf("group %q has non-numeric gid %q", p.Group, g.Gid)
+		}
+	}
+	return &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}, nil
+}
+
+func lookupUser(name string) (*user.User, error) {
+	if _, err := strconv.Atoi(name); err == nil {
+		if u, err := user.LookupId(name); err == nil {
+			return u, nil
+		}
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown user %q", name)
+	}
+	return u, nil
+}
+
+func lookupGroup(name string) (*user.Group, error) {
+	if _, err := strconv.Atoi(name); err == nil {
+		if g, err := user.LookupGroupId(name); err == nil {
+			return g, nil
+		}
+	}
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown group %q", name)
+	}
+	return g, nil
+}
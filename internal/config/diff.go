@@ -0,0 +1,27 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// DiffConfigs returns the sorted names of processes defined in both configs
+// whose definition changed in a way that needs a restart to take effect.
+// Fields that only affect the TUI (description, confirm_stop) are ignored.
+// Added and removed processes are not included.
+func DiffConfigs(old, new *Config) []string {
+	var changed []string
+	for name, newProc := range new.Processes {
+		oldProc, ok := old.Processes[name]
+		if !ok {
+			continue
+		}
+		oldProc.Description, newProc.Description = "", ""
+		oldProc.ConfirmStop, newProc.ConfirmStop = false, false
+		if !reflect.DeepEqual(oldProc, newProc) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
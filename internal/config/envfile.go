@@ -0,0 +1,114 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// parseEnvFile parses KEY=VALUE lines. Blank lines and lines starting with #
+// are ignored, an optional "export " prefix is stripped, and values wrapped in
+// matching single or double quotes are unquoted.
+func parseEnvFile(r io.Reader) (map[string]string, error) {
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// loadEnvFiles merges the global env_file into the global Env and each
+// process's env_file into its Env. Inline env values take precedence. Missing
+// files are skipped here and reported by Validate.
+func loadEnvFiles(cfg *Config) error {
+	home, _ := os.UserHomeDir()
+
+	env, err := mergeEnvFile(cfg.Env, cfg.EnvFile, home)
+	if err != nil {
+		return fmt.Errorf("env_file %s: %w", cfg.EnvFile, err)
+	}
+	cfg.Env = env
+
+	for name, proc := range cfg.Processes {
+		env, err := mergeEnvFile(proc.Env, proc.EnvFile, home)
+		if err != nil {
+			return fmt.Errorf("process %q: env_file %s: %w", name, proc.EnvFile, err)
+		}
+		proc.Env = env
+		cfg.Processes[name] = proc
+	}
+	return nil
+}
+
+// mergeEnvFile adds variables from path to env without overriding existing
+// keys. File values are expanded like inline env values.
+func mergeEnvFile(env map[string]string, path, home string) (map[string]string, error) {
+	if path == "" {
+		return env, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return env, nil
+	}
+	fileEnv, err := parseEnvFile(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if env == nil {
+		env = make(map[string]string)
+	}
+	for k, v := range fileEnv {
+		if _, ok := env[k]; ok {
+			continue
+		}
+		if home != "" {
+			v = expandTilde(v, home)
+		}
+		env[k] = os.ExpandEnv(v)
+	}
+	return env, nil
+}
+
+// applyGlobalEnv copies the shared env into every process. Process-level
+// values take precedence.
+func applyGlobalEnv(cfg *Config) {
+	if len(cfg.Env) == 0 {
+		return
+	}
+	for name, proc := range cfg.Processes {
+		merged := make(map[string]string, len(cfg.Env)+len(proc.Env))
+		for k, v := range cfg.Env {
+			merged[k] = v
+		}
+		for k, v := range proc.Env {
+			merged[k] = v
+		}
+		proc.Env = merged
+		cfg.Processes[name] = proc
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	input := `# secrets
+DB_HOST=localhost
+
+export DB_USER = admin
+DB_PASS="p@ss word"
+SINGLE='x=y'
+`
+	env, err := parseEnvFile(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"DB_HOST": "localhost",
+		"DB_USER": "admin",
+		"DB_PASS": "p@ss word",
+		"SINGLE":  "x=y",
+	}, env)
+}
+
+func TestParseEnvFile_Malformed(t *testing.T) {
+	_, err := parseEnvFile(strings.NewReader("OK=1\nnot a pair\n"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestLoad_EnvFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	envPath := filepath.Join(tmpDir, "app.env")
+	os.WriteFile(envPath, []byte("FROM_FILE=file\nOVERRIDDEN=file\n"), 0644)
+
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+processes:
+  a:
+    command: "echo a"
+    env_file: "`+envPath+`"
+    env:
+      OVERRIDDEN: inline
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, Validate(cfg))
+
+	env := cfg.Processes["a"].Env
+	assert.Equal(t, "file", env["FROM_FILE"])
+	assert.Equal(t, "inline", env["OVERRIDDEN"])
+}
+
+func TestValidate_MissingEnvFile(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]Process{
+			"a": {Command: "echo a", EnvFile: "/nonexistent/app.env"},
+		},
+	}
+	applyDefaults(cfg)
+
+	err := Validate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env_file /nonexistent/app.env does not exist")
+}
+
+func TestLoad_GlobalEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	envPath := filepath.Join(tmpDir, "shared.env")
+	os.WriteFile(envPath, []byte("SHARED_FILE=global-file\nLOG_LEVEL=from-file\n"), 0644)
+
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte(`version: 1
+env_file: "`+envPath+`"
+env:
+  AWS_PROFILE: dev
+  LOG_LEVEL: info
+processes:
+  plain:
+    command: "echo plain"
+  custom:
+    command: "echo custom"
+    env:
+      LOG_LEVEL: debug
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, Validate(cfg))
+
+	plain := cfg.Processes["plain"].Env
+	assert.Equal(t, "dev", plain["AWS_PROFILE"])
+	assert.Equal(t, "info", plain["LOG_LEVEL"])
+	assert.Equal(t, "global-file", plain["SHARED_FILE"])
+
+	custom := cfg.Processes["custom"].Env
+	assert.Equal(t, "dev", custom["AWS_PROFILE"])
+	assert.Equal(t, "debug", custom["LOG_LEVEL"])
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyActions lists the TUI actions that can be rebound in the keybindings
+// section.
+var KeyActions = []string{
+	"up", "down", "enter",
+	"start", "stop", "stop_only", "kill", "restart", "signal_usr1", "signal_usr2",
+	"start_group", "stop_group", "restart_group", "start_all", "stop_all",
+	"tab", "logs", "fullscreen", "all_logs",
+	"filter", "sort", "show_status", "inspect",
+	"next_match", "prev_match",
+	"colors", "levels", "timestamps", "wrap", "export", "clear_logs", "follow", "top", "bottom",
+	"help", "quit",
+}
+
+// DefaultKeys are the keys each action is bound to unless the keybindings
+// section overrides it.
+var DefaultKeys = map[string]KeyList{
+	"up":            {"up", "k"},
+	"down":          {"down", "j"},
+	"enter":         {"enter"},
+	"start":         {"s"},
+	"stop":          {"x"},
+	"stop_only":     {"alt+x"},
+	"kill":          {"K"},
+	"restart":       {"r"},
+	"signal_usr1":   {"1"},
+	"signal_usr2":   {"2"},
+	"start_group":   {"g"},
+	"stop_group":    {"G"},
+	"restart_group": {"R"},
+	"start_all":     {"a"},
+	"stop_all":      {"X"},
+	"tab":           {"tab"},
+	"logs":          {"l"},
+	"fullscreen":    {"f"},
+	"all_logs":      {"L"},
+	"filter":        {"/"},
+	"sort":          {"o"},
+	"show_status":   {"v"},
+	"inspect":       {"i"},
+	"next_match":    {"n"},
+	"prev_match":    {"N"},
+	"colors":        {"c"},
+	"levels":        {"e"},
+	"timestamps":    {"t"},
+	"wrap":          {"W"},
+	"export":        {"w"},
+	"clear_logs":    {"C"},
+	"follow":        {"F"},
+	"top":           {"g", "home"},
+	"bottom":        {"G", "end"},
+	"help":          {"?"},
+	"quit":          {"q", "ctrl+c"},
+}
+
+// keyPanels lists the actions each TUI panel responds to. A key may only be
+// bound once within a panel, but panels can share keys: g starts a group in
+// the process list and jumps to the top in the log panel. The full-screen
+// log view handles a subset of the log panel's actions.
+var keyPanels = map[string][]string{
+	"process list": {
+		"quit", "help", "filter", "sort", "show_status", "up", "down", "enter", "inspect",
+		"start", "stop", "stop_only", "kill", "restart", "signal_usr1", "signal_usr2",
+		"start_group", "stop_group", "restart_group", "start_all", "stop_all",
+		"export", "tab", "logs", "fullscreen", "all_logs",
+	},
+	"log panel": {
+		"tab", "fullscreen", "all_logs", "quit", "help", "filter", "next_match", "prev_match",
+		"colors", "levels", "timestamps", "wrap", "export", "clear_logs", "follow", "top", "bottom",
+	},
+}
+
+// namedKeys are the multi-character key names Bubble Tea reports.
+var namedKeys = map[string]bool{
+	"enter": true, "tab": true, "shift+tab": true, "esc": true, "space": true,
+	"backspace": true, "delete": true, "insert": true,
+	"up": true, "down": true, "left": true, "right": true,
+	"home": true, "end": true, "pgup": true, "pgdown": true,
+	"f1": true, "f2": true, "f3": true, "f4": true, "f5": true, "f6": true,
+	"f7": true, "f8": true, "f9": true, "f10": true, "f11": true, "f12": true,
+}
+
+// KeyList is one or more keys bound to an action. In YAML it may be a single
+// key or a list of keys.
+type KeyList []string
+
+// UnmarshalYAML accepts either a scalar key or a sequence of keys.
+func (k *KeyList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*k = KeyList{value.Value}
+		return nil
+	}
+	var keys []string
+	if err := value.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+// validKey reports whether key is a single character, a named key such as
+// "enter" or "pgdown", or a ctrl/alt combination.
+func validKey(key string) bool {
+	if len([]rune(key)) == 1 || namedKeys[key] {
+		return true
+	}
+	for _, prefix := range []string{"ctrl+", "alt+"} {
+		if rest, ok := strings.CutPrefix(key, prefix); ok {
+			return validKey(rest)
+		}
+	}
+	return false
+}
+
+// validateKeybindings reports unknown actions, unparseable keys, and keys
+// bound to more than one action in the same panel once the overrides are
+// merged over DefaultKeys.
+func validateKeybindings(bindings map[string]KeyList) []string {
+	known := make(map[string]bool, len(KeyActions))
+	for _, a := range KeyActions {
+		known[a] = true
+	}
+
+	actions := make([]string, 0, len(bindings))
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var errs []string
+	for _, action := range actions {
+		if !known[action] {
+			errs = append(errs, fmt.Sprintf("keybindings: unknown action %q", action))
+			continue
+		}
+		if len(bindings[action]) == 0 {
+			errs = append(errs, fmt.Sprintf("keybindings: action %q has no keys", action))
+		}
+		for _, key := range bindings[action] {
+			if !validKey(key) {
+				errs = append(errs, fmt.Sprintf("keybindings: invalid key %q for %q (multi-key sequences are not supported)", key, action))
+			}
+		}
+	}
+
+	merged := make(map[string]KeyList, len(DefaultKeys))
+	for action, list := range DefaultKeys {
+		merged[action] = list
+	}
+	for action, list := range bindings {
+		if known[action] && len(list) > 0 {
+			merged[action] = list
+		}
+	}
+	describe := func(action string) string {
+		if _, ok := bindings[action]; ok {
+			return fmt.Sprintf("%q", action)
+		}
+		return fmt.Sprintf("%q (default)", action)
+	}
+
+	panels := make([]string, 0, len(keyPanels))
+	for panel := range keyPanels {
+		panels = append(panels, panel)
+	}
+	sort.Strings(panels)
+
+	reported := make(map[[3]string]bool) // the same clash can occur in several panels
+	for _, panel := range panels {
+		owner := make(map[string]string) // key -> action
+		for _, action := range keyPanels[panel] {
+			for _, key := range merged[action] {
+				other, ok := owner[key]
+				if !ok {
+					owner[key] = action
+					continue
+				}
+				if other == action || reported[[3]string{key, other, action}] {
+					continue
+				}
+				reported[[3]string{key, other, action}] = true
+				errs = append(errs, fmt.Sprintf("keybindings: key %q is bound to both %s and %s in the %s",
+					key, describe(other), describe(action), panel))
+			}
+		}
+	}
+	return errs
+}
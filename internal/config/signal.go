@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+// DefaultStopSignal is sent to a process when no stop_signal is configured.
+const DefaultStopSignal = syscall.SIGTERM
+
+var signalNames = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+// SignalName returns the name of sig as accepted by ParseSignal, such as
+// "SIGUSR1", or sig's description if it has no name here.
+func SignalName(sig syscall.Signal) string {
+	for name, s := range signalNames {
+		if s == sig {
+			return name
+		}
+	}
+	return sig.String()
+}
+
+// ParseSignal converts a signal name such as "SIGINT" or "int" to a
+// syscall.Signal. An empty name yields DefaultStopSignal.
+func ParseSignal(name string) (syscall.Signal, error) {
+	if name == "" {
+		return DefaultStopSignal, nil
+	}
+	upper := strings.ToUpper(name)
+	if !strings.HasPrefix(upper, "SIG") {
+		upper = "SIG" + upper
+	}
+	sig, ok := signalNames[upper]
+	if !ok {
+		return 0, fmt.Errorf("unknown signal %q", name)
+	}
+	return sig, nil
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TemplateBuiltins are the template variables shepherd provides itself; vars
+// may not redefine them.
+var TemplateBuiltins = []string{"name"}
+
+// templated reports whether a process's command is rendered as a template.
+// Only configs that use vars opt in, so commands that happen to contain
+// "{{", such as docker --format strings, keep working.
+func (c *Config) templated(proc Process) bool {
+	return c.Vars != nil || proc.Vars != nil
+}
+
+// TemplateData returns the variables available to the named process's
+// command template: the top-level vars, overridden by the process's own, plus
+// the built-ins.
+func (c *Config) TemplateData(name string) map[string]string {
+	data := make(map[string]string)
+	for k, v := range c.Vars {
+		data[k] = v
+	}
+	for k, v := range c.Processes[name].Vars {
+		data[k] = v
+	}
+	data["name"] = name
+	return data
+}
+
+// renderCommand renders the named process's command and args. Referring to
+// an undefined variable is an error.
+func (c *Config) renderCommand(name string) (command string, args []string, err error) {
+	proc := c.Processes[name]
+	data := c.TemplateData(name)
+	if command, err = renderTemplate(proc.Command, data); err != nil {
+		return "", nil, err
+	}
+	if proc.Args != nil {
+		args = make([]string, len(proc.Args))
+		for i, a := range proc.Args {
+			if args[i], err = renderTemplate(a, data); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return command, args, nil
+}
+
+func renderTemplate(text string, data map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("command").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// renderCommands renders every templated process's command in place. A
+// command that fails to render is left as written, and the error is kept for
+// Validate to report.
+func renderCommands(cfg *Config) {
+	cfg.rendered = true
+	for _, name := range sortedProcessNames(cfg) {
+		proc := cfg.Processes[name]
+		if !cfg.templated(proc) {
+			continue
+		}
+		command, args, err := cfg.renderCommand(name)
+		if err != nil {
+			cfg.templateErrs = append(cfg.templateErrs, fmt.Sprintf("process %q: command: %s", name, err))
+			continue
+		}
+		proc.Command, proc.Args = command, args
+		cfg.Processes[name] = proc
+	}
+}
+
+// templateErrors returns the problems with vars and command templates. A
+// config from Load has already been rendered, so the errors found then are
+// returned rather than rendering again.
+func templateErrors(cfg *Config) []string {
+	var errs []string
+	for _, b := range TemplateBuiltins {
+		if _, ok := cfg.Vars[b]; ok {
+			errs = append(errs, fmt.Sprintf("vars: %q is built in and can't be redefined", b))
+		}
+	}
+	for _, name := range sortedProcessNames(cfg) {
+		for _, b := range TemplateBuiltins {
+			if _, ok := cfg.Processes[name].Vars[b]; ok {
+				errs = append(errs, fmt.Sprintf("process %q: vars: %q is built in and can't be redefined", name, b))
+			}
+		}
+	}
+
+	if cfg.rendered {
+		return append(errs, cfg.templateErrs...)
+	}
+	for _, name := range sortedProcessNames(cfg) {
+		if !cfg.templated(cfg.Processes[name]) {
+			continue
+		}
+		if _, _, err := cfg.renderCommand(name); err != nil {
+			errs = append(errs, fmt.Sprintf("process %q: command: %s", name, err))
+		}
+	}
+	return errs
+}
+
+func sortedProcessNames(cfg *Config) []string {
+	names := make([]string, 0, len(cfg.Processes))
+	for name := range cfg.Processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_RendersCommandTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`vars:
+  host: bastion.example.com
+  port: "5432"
+processes:
+  db-tunnel:
+    command: "ssh -N -L {{.port}}:db:5432 {{.host}}"
+  cache-tunnel:
+    command: "ssh -N -L {{.port}}:cache:6379 {{.host}}"
+    vars:
+      port: "6379"
+  named:
+    command: ["echo", "{{.name}}"]
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, Validate(cfg))
+
+	assert.Equal(t, "ssh -N -L 5432:db:5432 bastion.example.com", cfg.Processes["db-tunnel"].Command)
+	assert.Equal(t, "ssh -N -L 6379:cache:6379 bastion.example.com", cfg.Processes["cache-tunnel"].Command)
+	assert.Equal(t, []string{"echo", "named"}, cfg.Processes["named"].Args)
+}
+
+func TestLoad_UndefinedTemplateVar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`vars: {}
+processes:
+  app:
+    command: "echo {{.missing}}"
+`), 0644)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "echo {{.missing}}", cfg.Processes["app"].Command)
+
+	err = Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `process "app": command:`)
+	assert.Contains(t, err.Error(), `map has no entry for key "missing"`)
+}
+
+func TestValidate_TemplateVars(t *testing.T) {
+	cfg := &Config{
+		Vars: map[string]string{"name": "x"},
+		Processes: map[string]Process{
+			"app": {Command: "echo {{.port"},
+			"web": {Command: "echo {{.port}}", Vars: map[string]string{"port": "80"}},
+		},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `vars: "name" is built in and can't be redefined`)
+	assert.Contains(t, err.Error(), `process "app": command: template:`)
+	assert.NotContains(t, err.Error(), `process "web"`)
+}
+
+func TestValidate_CommandsWithoutVarsAreNotTemplates(t *testing.T) {
+	cfg := &Config{Processes: map[string]Process{
+		"ps": {Command: "docker ps --format '{{.Names}}'"},
+	}}
+	assert.NoError(t, Validate(cfg))
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// ThemeColors lists the semantic color names accepted in the theme section.
+var ThemeColors = []string{
+	"running", "healthy", "failed", "retrying", "stopped", "completed", "starting",
+	"accent", "subtle", "dim",
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether c is a hex color ("#f80", "#ff8800") or an ANSI
+// color number from 0 to 255.
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// validateTheme reports unknown color names and unparseable color values.
+func validateTheme(theme map[string]string) []string {
+	known := make(map[string]bool, len(ThemeColors))
+	for _, c := range ThemeColors {
+		known[c] = true
+	}
+
+	names := make([]string, 0, len(theme))
+	for name := range theme {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []string
+	for _, name := range names {
+		if !known[name] {
+			errs = append(errs, fmt.Sprintf("theme: unknown color %q", name))
+			continue
+		}
+		if !validColor(theme[name]) {
+			errs = append(errs, fmt.Sprintf("theme: %s color %q must be a hex color like #ff8800 or an ANSI color 0-255", name, theme[name]))
+		}
+	}
+	return errs
+}
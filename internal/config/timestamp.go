@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var timestampPresets = map[string]string{
+	"time":      "15:04:05",
+	"time_ms":   "15:04:05.000",
+	"datetime":  "2006-01-02 15:04:05",
+	"rfc3339":   time.RFC3339,
+	"rfc3339ms": "2006-01-02T15:04:05.000Z07:00",
+}
+
+// ParseTimestampFormat converts a preset name such as "rfc3339" or a Go
+// time layout such as "Jan 2 15:04:05" to a layout. An empty format yields
+// an empty layout, leaving the logging default in place.
+func ParseTimestampFormat(format string) (string, error) {
+	if format == "" {
+		return "", nil
+	}
+	if layout, ok := timestampPresets[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+	// A layout with no reference fields formats every time the same way.
+	a := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	b := time.Date(2007, 2, 3, 16, 5, 6, 7e8, time.UTC)
+	if a.Format(format) == b.Format(format) {
+		return "", fmt.Errorf("invalid log_timestamp_format %q: not a preset and has no time fields", format)
+	}
+	return format, nil
+}
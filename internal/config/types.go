@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Duration wraps time.Duration to support YAML string unmarshaling (e.g., "2s", "500ms").
@@ -29,11 +34,55 @@ func (d Duration) MarshalYAML() (interface{}, error) {
 	return time.Duration(d).String(), nil
 }
 
+// DefaultShell runs string commands when neither the process nor the config
+// sets shell.
+const DefaultShell = "sh"
+
+// CurrentVersion is the config schema version this build understands. A
+// config that omits version is treated as this version.
+const CurrentVersion = 1
+
 type Config struct {
-	Version   int                `yaml:"version"`
-	Stacks    map[string]Stack   `yaml:"stacks"`
-	Groups    map[string]Group   `yaml:"groups"`
-	Processes map[string]Process `yaml:"processes"`
+	Version       int                `yaml:"version"`
+	Env           map[string]string  `yaml:"env"`      // shared by all processes
+	EnvFile       string             `yaml:"env_file"` // shared by all processes
+	Vars          map[string]string  `yaml:"vars"`     // template variables for commands; see TemplateData
+	Shell         string             `yaml:"shell"`    // default shell for string commands; "sh" if unset
+	Notify        bool               `yaml:"notify"`   // desktop notification when a process fails for good
+	Control       ControlConfig      `yaml:"control"`
+	Session       SessionConfig      `yaml:"session"`
+	StartLimit    int                `yaml:"max_concurrent_starts"` // processes allowed to be starting at once; 0 means no limit
+	LogTimestamp  string             `yaml:"log_timestamp_format"`  // preset name or Go time layout; see ParseTimestampFormat
+	HealthTimeout Duration           `yaml:"health_timeout"`        // how long dependents wait for readiness; 0 means DefaultStartTimeout
+	HealthPoll    Duration           `yaml:"health_poll_interval"`  // how often readiness is checked; 0 means DefaultHealthPollInterval
+	Webhooks      []Webhook          `yaml:"webhooks"`
+	Keybindings   map[string]KeyList `yaml:"keybindings"` // TUI action -> keys, merged over the defaults
+	Theme         map[string]string  `yaml:"theme"`       // TUI color name -> hex or ANSI color, merged over the defaults
+	Defaults      Defaults           `yaml:"defaults"`
+	Stacks        map[string]Stack   `yaml:"stacks"`
+	Groups        map[string]Group   `yaml:"groups"`
+	Processes     map[string]Process `yaml:"processes"`
+
+	rendered     bool     // commands were rendered by Load
+	templateErrs []string // render errors found by Load, reported by Validate
+}
+
+// ControlConfig configures the Unix socket control server.
+type ControlConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Socket  string `yaml:"socket"`
+}
+
+// Defaults holds settings every process inherits unless it sets its own.
+type Defaults struct {
+	Retry RetryConfig `yaml:"retry"` // merged field by field under each process's retry
+}
+
+// SessionConfig controls remembering which processes were running when
+// shepherd exited, so the same set can be started on the next launch.
+type SessionConfig struct {
+	Restore bool   `yaml:"restore"`
+	File    string `yaml:"file"`
 }
 
 type Stack struct {
@@ -44,15 +93,247 @@ type Stack struct {
 type Group struct {
 	Description string   `yaml:"description"`
 	Processes   []string `yaml:"processes"`
+	DependsOn   []string `yaml:"depends_on"` // groups whose processes start before this group's
 }
 
 type Process struct {
-	Description string            `yaml:"description"`
-	Command     string            `yaml:"command"`
-	WorkingDir  string            `yaml:"working_dir"`
-	Env         map[string]string `yaml:"env"`
-	DependsOn   []string          `yaml:"depends_on"`
-	Retry       RetryConfig       `yaml:"retry"`
+	Description   string            `yaml:"description"`
+	Tags          []string          `yaml:"tags"` // labels for starting several processes at once as tag:<name>
+	Command       string            `yaml:"command"`
+	Args          []string          `yaml:"-"`     // set when command is given as a list; exec'd without a shell
+	Vars          map[string]string `yaml:"vars"`  // template variables for this command, over the top-level vars
+	Shell         string            `yaml:"shell"` // runs a string command as <shell> -c; ignored for list commands
+	Nice          int               `yaml:"nice"`  // scheduling priority from -20 (highest) to 19; 0 leaves it unchanged
+	User          string            `yaml:"user"`  // run as this user (name or uid); needs privilege to switch
+	Group         string            `yaml:"group"` // run with this group (name or gid); defaults to the user's primary group
+	WorkingDir    string            `yaml:"working_dir"`
+	Env           map[string]string `yaml:"env"`
+	EnvFile       string            `yaml:"env_file"`
+	ClearEnv      bool              `yaml:"clear_env"`   // start from an empty environment instead of shepherd's
+	InheritEnv    []string          `yaml:"inherit_env"` // shepherd's variables passed through; implies clear_env
+	Stdin         string            `yaml:"stdin"`       // written to the process's stdin after start, then closed
+	StdinFile     string            `yaml:"stdin_file"`  // like stdin, but read from a file at each start
+	DependsOn     []Dependency      `yaml:"depends_on"`
+	Retry         RetryConfig       `yaml:"retry"`
+	HealthCheck   HealthCheck       `yaml:"health_check"`
+	Watch         WatchConfig       `yaml:"watch"`
+	StopSignal    string            `yaml:"stop_signal"`
+	StopTimeout   Duration          `yaml:"stop_timeout"`
+	ConfirmStop   bool              `yaml:"confirm_stop"` // TUI asks before stopping this process
+	Restart       string            `yaml:"restart"`
+	Schedule      string            `yaml:"schedule"` // cron expression; starting the process arms the schedule instead of running it
+	LogFile       string            `yaml:"log_file"`
+	LogBufferSize int               `yaml:"log_buffer_size"`    // lines of history kept in memory; 0 means logging.DefaultBufferSize
+	LogMaxBytes   int               `yaml:"log_buffer_bytes"`   // total bytes of history kept in memory; 0 means no limit
+	LogMaxLine    int               `yaml:"log_max_line"`       // longer lines are truncated in memory; 0 means logging.DefaultMaxLineLength
+	StartupDelay  *Duration         `yaml:"startup_delay"`      // nil means DefaultStartupDelay
+	StartTimeout  *Duration         `yaml:"start_timeout"`      // nil means dependents wait health_timeout and the process itself has no limit
+	SuccessExit   []int             `yaml:"success_exit_codes"` // exit codes that count as a clean exit; empty means [0]
+}
+
+// UnmarshalYAML accepts command as either a string (run via sh -c) or a list
+// of strings (exec'd directly). A list is stored in Args.
+func (p *Process) UnmarshalYAML(value *yaml.Node) error {
+	type rawProcess Process
+
+	node := *value
+	var args []string
+	if value.Kind == yaml.MappingNode {
+		node.Content = make([]*yaml.Node, 0, len(value.Content))
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			k, v := value.Content[i], value.Content[i+1]
+			if k.Value == "command" && v.Kind == yaml.SequenceNode {
+				if err := v.Decode(&args); err != nil {
+					return fmt.Errorf("command: %w", err)
+				}
+				continue
+			}
+			node.Content = append(node.Content, k, v)
+		}
+	}
+
+	var raw rawProcess
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*p = Process(raw)
+	if args != nil {
+		p.Args = args
+	}
+	return nil
+}
+
+// Dependency names a process that must be started first. In YAML it may be
+// written as a plain name or as a mapping with extra options.
+type Dependency struct {
+	Name string `yaml:"name"`
+	// Optional dependencies are started first when possible, but their
+	// failure does not block or fail the dependent process.
+	Optional bool `yaml:"optional"`
+	// Condition is DependencyHealthy (the default) or DependencyStarted.
+	Condition string `yaml:"condition"`
+}
+
+// Dependency conditions control when a dependent may start.
+const (
+	// DependencyHealthy waits for the dependency's health check to pass, or
+	// for its startup delay to elapse when it has no health check.
+	DependencyHealthy = "healthy"
+	// DependencyStarted only waits for the dependency to be running.
+	DependencyStarted = "started"
+)
+
+// UnmarshalYAML accepts either a bare process name or a mapping.
+func (d *Dependency) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		d.Name = value.Value
+		return nil
+	}
+	type rawDependency Dependency
+	var raw rawDependency
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	*d = Dependency(raw)
+	return nil
+}
+
+// TagPrefix marks a name as a tag rather than a stack, group, or process,
+// e.g. "tag:backend".
+const TagPrefix = "tag:"
+
+// HasTag reports whether the process carries tag.
+func (p Process) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TaggedProcesses returns the sorted names of the processes carrying tag.
+func (c *Config) TaggedProcesses(tag string) []string {
+	var names []string
+	for name, proc := range c.Processes {
+		if proc.HasTag(tag) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DependencyNames returns the names of all dependencies, optional or not.
+func (p Process) DependencyNames() []string {
+	names := make([]string, len(p.DependsOn))
+	for i, dep := range p.DependsOn {
+		names[i] = dep.Name
+	}
+	return names
+}
+
+// IsolatedEnv reports whether the process gets only the inherit_env variables
+// of shepherd's environment rather than all of it.
+func (p Process) IsolatedEnv() bool {
+	return p.ClearEnv || len(p.InheritEnv) > 0
+}
+
+// StartupDelayDuration returns the configured startup delay, or
+// DefaultStartupDelay when unset.
+func (p Process) StartupDelayDuration() time.Duration {
+	if p.StartupDelay == nil {
+		return DefaultStartupDelay
+	}
+	return p.StartupDelay.Duration()
+}
+
+// IsSuccessExit reports whether code counts as a clean exit for this process.
+func (p Process) IsSuccessExit(code int) bool {
+	if len(p.SuccessExit) == 0 {
+		return code == 0
+	}
+	for _, c := range p.SuccessExit {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandLine returns the command as a single human-readable string.
+func (p Process) CommandLine() string {
+	if len(p.Args) > 0 {
+		return strings.Join(p.Args, " ")
+	}
+	return p.Command
+}
+
+// Restart policies control what happens when a process exits on its own.
+const (
+	// RestartOnFailure retries crashed processes according to the retry config.
+	RestartOnFailure = "on-failure"
+	// RestartAlways also restarts processes that exit cleanly, and retries
+	// crashes even when retry.enabled is false.
+	RestartAlways = "always"
+	// RestartNever leaves a crashed process failed regardless of retry config.
+	RestartNever = "never"
+)
+
+// HealthCheck describes how to probe whether a running process is ready.
+// Exactly one of TCP, HTTP, or Command should be set.
+type HealthCheck struct {
+	TCP      string   `yaml:"tcp"`
+	HTTP     string   `yaml:"http"`
+	Command  string   `yaml:"command"`
+	Interval Duration `yaml:"interval"`
+	Timeout  Duration `yaml:"timeout"`
+}
+
+// Configured reports whether any health check probe is defined.
+func (h HealthCheck) Configured() bool {
+	return h.TCP != "" || h.HTTP != "" || h.Command != ""
+}
+
+// WatchConfig restarts a running process when files under Paths change.
+// Directories are watched recursively, skipping hidden subdirectories.
+type WatchConfig struct {
+	Paths    []string `yaml:"paths"`    // relative paths are resolved against working_dir
+	Patterns []string `yaml:"patterns"` // globs matched against file names, e.g. "*.go"; empty matches every file
+	Debounce Duration `yaml:"debounce"` // quiet period before restarting; 0 means DefaultWatchDebounce
+}
+
+// DefaultWatchDebounce collapses a burst of changes, such as a git checkout,
+// into a single restart.
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// Configured reports whether any watch paths are defined.
+func (w WatchConfig) Configured() bool {
+	return len(w.Paths) > 0
+}
+
+// DebounceDuration returns the configured debounce, or DefaultWatchDebounce
+// when unset.
+func (w WatchConfig) DebounceDuration() time.Duration {
+	if w.Debounce == 0 {
+		return DefaultWatchDebounce
+	}
+	return w.Debounce.Duration()
+}
+
+// Matches reports whether a change to the file at path should trigger a
+// restart.
+func (w WatchConfig) Matches(path string) bool {
+	if len(w.Patterns) == 0 {
+		return true
+	}
+	base := filepath.Base(path)
+	for _, pattern := range w.Patterns {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
 }
 
 type RetryConfig struct {
@@ -61,6 +342,119 @@ type RetryConfig struct {
 	InitialBackoff    Duration `yaml:"initial_backoff"`
 	MaxBackoff        Duration `yaml:"max_backoff"`
 	BackoffMultiplier float64  `yaml:"backoff_multiplier"`
+	ResetAfter        Duration `yaml:"reset_after"` // crashes after running this long start retries afresh; 0 disables
+	Jitter            *float64 `yaml:"jitter"`      // fraction of the backoff to randomize by; nil means DefaultJitter
+	Mode              string   `yaml:"mode"`        // RetryExponential (default) or RetryFixed
+
+	set map[string]bool // keys present in the YAML, so an explicit false or 0 isn't replaced by a default
+}
+
+// UnmarshalYAML records which keys were given so inherited defaults only fill
+// in the fields a process left out.
+func (r *RetryConfig) UnmarshalYAML(value *yaml.Node) error {
+	type rawRetryConfig RetryConfig
+	var raw rawRetryConfig
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	*r = RetryConfig(raw)
+	if value.Kind == yaml.MappingNode {
+		r.set = make(map[string]bool, len(value.Content)/2)
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			r.set[value.Content[i].Value] = true
+		}
+	}
+	return nil
+}
+
+// inherit fills the fields of r that were not set explicitly from base.
+func (r RetryConfig) inherit(base RetryConfig) RetryConfig {
+	if !r.set["enabled"] {
+		r.Enabled = base.Enabled
+	}
+	if !r.set["max_attempts"] && base.set["max_attempts"] {
+		r.MaxAttempts = base.MaxAttempts
+	}
+	if r.InitialBackoff == 0 {
+		r.InitialBackoff = base.InitialBackoff
+	}
+	if r.MaxBackoff == 0 {
+		r.MaxBackoff = base.MaxBackoff
+	}
+	if r.BackoffMultiplier == 0 {
+		r.BackoffMultiplier = base.BackoffMultiplier
+	}
+	if r.ResetAfter == 0 {
+		r.ResetAfter = base.ResetAfter
+	}
+	if r.Jitter == nil {
+		r.Jitter = base.Jitter
+	}
+	if r.Mode == "" {
+		r.Mode = base.Mode
+	}
+	return r
+}
+
+// Retry modes control how the backoff changes between attempts.
+const (
+	// RetryExponential multiplies the backoff by backoff_multiplier after each
+	// attempt, up to max_backoff.
+	RetryExponential = "exponential"
+	// RetryFixed waits initial_backoff before every attempt.
+	RetryFixed = "fixed"
+)
+
+// DefaultJitter spreads each retry backoff by up to 10% either way.
+const DefaultJitter = 0.1
+
+// JitterFraction returns the configured jitter, or DefaultJitter when unset.
+func (r RetryConfig) JitterFraction() float64 {
+	if r.Jitter == nil {
+		return DefaultJitter
+	}
+	return *r.Jitter
+}
+
+// DefaultStartupDelay is how long a dependency without a health check must run
+// before its dependents start.
+const DefaultStartupDelay = 2 * time.Second
+
+// DefaultStartTimeout is how long dependents wait for a dependency to become
+// ready when neither its start_timeout nor health_timeout is set.
+const DefaultStartTimeout = 60 * time.Second
+
+// DefaultHealthPollInterval is how often readiness is checked when
+// health_poll_interval is unset.
+const DefaultHealthPollInterval = 200 * time.Millisecond
+
+// StartTimeout returns how long dependents wait for the named process to
+// become ready: its start_timeout, else health_timeout, else
+// DefaultStartTimeout.
+func (c *Config) StartTimeout(name string) time.Duration {
+	if t := c.Processes[name].StartTimeout; t != nil {
+		return t.Duration()
+	}
+	if c.HealthTimeout > 0 {
+		return c.HealthTimeout.Duration()
+	}
+	return DefaultStartTimeout
+}
+
+// HealthPollInterval returns health_poll_interval, or
+// DefaultHealthPollInterval when unset.
+func (c *Config) HealthPollInterval() time.Duration {
+	if c.HealthPoll > 0 {
+		return c.HealthPoll.Duration()
+	}
+	return DefaultHealthPollInterval
+}
+
+func DefaultHealthCheck() HealthCheck {
+	return HealthCheck{
+		Interval: Duration(1 * time.Second),
+		Timeout:  Duration(5 * time.Second),
+	}
 }
 
 func DefaultRetryConfig() RetryConfig {
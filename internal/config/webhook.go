@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// WebhookRecovered is the webhook event for a process that had failed or was
+// retrying and is running again.
+const WebhookRecovered = "recovered"
+
+// WebhookEvents lists the values accepted in a webhook's events list. Apart
+// from WebhookRecovered, each matches a process's new status.
+var WebhookEvents = []string{
+	"failed", WebhookRecovered,
+	"starting", "running", "healthy", "retrying", "stopping", "stopped", "completed", "scheduled",
+}
+
+// DefaultWebhookEvents is used when a webhook doesn't list any events.
+var DefaultWebhookEvents = []string{"failed", WebhookRecovered}
+
+// DefaultWebhookTimeout bounds a single webhook POST when timeout is unset.
+const DefaultWebhookTimeout = 5 * time.Second
+
+// Webhook posts process state changes to a URL.
+type Webhook struct {
+	URL     string   `yaml:"url"`
+	Events  []string `yaml:"events"`  // empty means DefaultWebhookEvents
+	Timeout Duration `yaml:"timeout"` // per attempt; 0 means DefaultWebhookTimeout
+}
+
+// Fires reports whether the webhook is subscribed to event.
+func (w Webhook) Fires(event string) bool {
+	events := w.Events
+	if len(events) == 0 {
+		events = DefaultWebhookEvents
+	}
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeoutDuration returns the per-attempt timeout, applying the default.
+func (w Webhook) TimeoutDuration() time.Duration {
+	if w.Timeout <= 0 {
+		return DefaultWebhookTimeout
+	}
+	return w.Timeout.Duration()
+}
+
+// validateWebhooks reports webhooks without an http(s) URL, unknown events,
+// and negative timeouts.
+func validateWebhooks(hooks []Webhook) []string {
+	known := make(map[string]bool, len(WebhookEvents))
+	for _, e := range WebhookEvents {
+		known[e] = true
+	}
+
+	var errs []string
+	for i, w := range hooks {
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Sprintf("webhooks[%d]: url %q must be an http or https URL", i, w.URL))
+		}
+		for _, e := range w.Events {
+			if !known[e] {
+				errs = append(errs, fmt.Sprintf("webhooks[%d]: unknown event %q", i, e))
+			}
+		}
+		if w.Timeout < 0 {
+			errs = append(errs, fmt.Sprintf("webhooks[%d]: timeout must not be negative", i))
+		}
+	}
+	return errs
+}
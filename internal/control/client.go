@@ -0,0 +1,42 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// dialTimeout bounds how long Call waits to connect to the socket.
+const dialTimeout = 2 * time.Second
+
+// Call sends a single request to the control server at path and returns its
+// response. A non-nil error means the server could not be reached or replied
+// with malformed data; request-level failures are reported in Response.Error.
+func Call(path string, req Request) (*Response, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", path, err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+		return nil, fmt.Errorf("reading response: connection closed")
+	}
+
+	var resp Response
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return &resp, nil
+}
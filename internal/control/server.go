@@ -0,0 +1,208 @@
+// Package control implements a Unix domain socket server that lets other
+// shepherd invocations query and control a running instance.
+//
+// The protocol is newline-delimited JSON: each line sent by the client is a
+// Request, and the server replies with exactly one Response line.
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/frontendtony/shepherd/internal/logging"
+	"github.com/frontendtony/shepherd/internal/process"
+)
+
+// Actions understood by the server.
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionStatus  = "status"
+	ActionLogs    = "logs"
+)
+
+// Request is a single command sent to the control server.
+type Request struct {
+	Action string `json:"action"`
+	Name   string `json:"name,omitempty"`
+	// For ActionLogs: Tail limits the reply to the last Tail lines (0 for all
+	// buffered lines); a non-zero Since instead returns only lines written
+	// after that sequence number.
+	Tail  int    `json:"tail,omitempty"`
+	Since uint64 `json:"since,omitempty"`
+}
+
+// Response is the server's reply to a Request.
+type Response struct {
+	OK     bool                   `json:"ok"`
+	Error  string                 `json:"error,omitempty"`
+	States []process.ProcessState `json:"states,omitempty"`
+	Lines  []string               `json:"lines,omitempty"`
+	Seq    uint64                 `json:"seq,omitempty"` // log sequence number to pass as Since
+}
+
+// Server serves control requests for a ProcessManager over a Unix socket.
+type Server struct {
+	mgr  *process.ProcessManager
+	path string
+
+	mu sync.Mutex
+	ln net.Listener
+	wg sync.WaitGroup
+}
+
+// NewServer creates a control server that will listen on path.
+func NewServer(mgr *process.ProcessManager, path string) *Server {
+	return &Server{mgr: mgr, path: path}
+}
+
+// Start begins listening. It fails if another instance is already serving on
+// the socket; a stale socket file left by a crashed instance is removed.
+func (s *Server) Start() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("creating socket directory: %w", err)
+	}
+
+	if _, err := os.Stat(s.path); err == nil {
+		if conn, err := net.DialTimeout("unix", s.path, time.Second); err == nil {
+			conn.Close()
+			return fmt.Errorf("another shepherd instance is listening on %s", s.path)
+		}
+		if err := os.Remove(s.path); err != nil {
+			return fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.path, err)
+	}
+
+	s.mu.Lock()
+	s.ln = ln
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.acceptLoop(ln)
+	return nil
+}
+
+// Close stops the server and removes the socket file.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	ln := s.ln
+	s.ln = nil
+	s.mu.Unlock()
+
+	if ln == nil {
+		return nil
+	}
+	err := ln.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) acceptLoop(ln net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Warn("control server accept failed", "error", err)
+			}
+			return
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = Response{Error: fmt.Sprintf("invalid request: %s", err)}
+		} else {
+			resp = s.handle(req)
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(req Request) Response {
+	var err error
+	switch req.Action {
+	case ActionStart:
+		var res *process.StartResult
+		if res, err = s.mgr.StartByName(req.Name); err == nil {
+			err = res.Err()
+		}
+	case ActionStop:
+		err = s.mgr.StopProcess(req.Name)
+	case ActionRestart:
+		err = s.mgr.RestartProcess(req.Name)
+	case ActionStatus:
+		return s.status(req.Name)
+	case ActionLogs:
+		return s.logs(req)
+	default:
+		err = fmt.Errorf("unknown action: %q", req.Action)
+	}
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	return Response{OK: true}
+}
+
+// status returns all process states sorted by name, or only the named one.
+func (s *Server) status(name string) Response {
+	if name != "" {
+		st, ok := s.mgr.GetState(name)
+		if !ok {
+			return Response{Error: fmt.Sprintf("unknown process: %s", name)}
+		}
+		return Response{OK: true, States: []process.ProcessState{st}}
+	}
+
+	states := s.mgr.GetAllStates()
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return Response{OK: true, States: states}
+}
+
+// logs returns buffered log lines for the named process.
+func (s *Server) logs(req Request) Response {
+	buf := s.mgr.GetLogBuffer(req.Name)
+	if buf == nil {
+		return Response{Error: fmt.Sprintf("unknown process: %s", req.Name)}
+	}
+
+	var entries []logging.Entry
+	var seq uint64
+	if req.Since > 0 {
+		entries, seq = buf.Since(req.Since)
+	} else {
+		entries, seq = buf.Tail(req.Tail)
+	}
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = e.String()
+	}
+	return Response{OK: true, Lines: lines, Seq: seq}
+}
@@ -0,0 +1,120 @@
+//go:build !ci
+
+package control
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"sleeper": {Command: "sleep 3600"},
+		},
+	}
+	mgr, err := process.NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(mgr.Shutdown)
+
+	dir, err := os.MkdirTemp("", "shepherd")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "s.sock")
+	srv := NewServer(mgr, path)
+	require.NoError(t, srv.Start())
+	t.Cleanup(func() { srv.Close() })
+
+	return srv, path
+}
+
+func TestServer_StartStatusStop(t *testing.T) {
+	_, path := newTestServer(t)
+
+	resp, err := Call(path, Request{Action: ActionStart, Name: "sleeper"})
+	require.NoError(t, err)
+	assert.True(t, resp.OK, resp.Error)
+
+	resp, err = Call(path, Request{Action: ActionStatus})
+	require.NoError(t, err)
+	require.Len(t, resp.States, 1)
+	assert.Equal(t, "sleeper", resp.States[0].Name)
+	assert.Equal(t, process.StatusRunning, resp.States[0].Status)
+
+	resp, err = Call(path, Request{Action: ActionStop, Name: "sleeper"})
+	require.NoError(t, err)
+	assert.True(t, resp.OK, resp.Error)
+
+	resp, err = Call(path, Request{Action: ActionStatus, Name: "sleeper"})
+	require.NoError(t, err)
+	require.Len(t, resp.States, 1)
+	assert.Equal(t, process.StatusStopped, resp.States[0].Status)
+}
+
+func TestServer_UnknownAction(t *testing.T) {
+	_, path := newTestServer(t)
+
+	resp, err := Call(path, Request{Action: "explode"})
+	require.NoError(t, err)
+	assert.False(t, resp.OK)
+	assert.Contains(t, resp.Error, "unknown action")
+}
+
+func TestServer_UnknownProcess(t *testing.T) {
+	_, path := newTestServer(t)
+
+	for _, action := range []string{ActionStop, ActionRestart} {
+		resp, err := Call(path, Request{Action: action, Name: "nope"})
+		require.NoError(t, err, action)
+		assert.False(t, resp.OK, action)
+		assert.Equal(t, "unknown process: nope", resp.Error, action)
+	}
+
+	// The server is still up.
+	resp, err := Call(path, Request{Action: ActionStatus})
+	require.NoError(t, err)
+	assert.Len(t, resp.States, 1)
+}
+
+func TestServer_RefusesSecondInstance(t *testing.T) {
+	srv, path := newTestServer(t)
+
+	other := NewServer(srv.mgr, path)
+	err := other.Start()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "another shepherd instance")
+}
+
+func TestServer_Logs(t *testing.T) {
+	srv, path := newTestServer(t)
+
+	buf := srv.mgr.GetLogBuffer("sleeper")
+	for _, l := range []string{"one", "two", "three"} {
+		buf.WriteString(l)
+	}
+
+	resp, err := Call(path, Request{Action: ActionLogs, Name: "sleeper", Tail: 2})
+	require.NoError(t, err)
+	assert.True(t, resp.OK, resp.Error)
+	assert.Equal(t, []string{"two", "three"}, resp.Lines)
+	assert.Equal(t, uint64(3), resp.Seq)
+
+	buf.WriteString("four")
+	resp, err = Call(path, Request{Action: ActionLogs, Name: "sleeper", Since: resp.Seq})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"four"}, resp.Lines)
+
+	resp, err = Call(path, Request{Action: ActionLogs, Name: "missing"})
+	require.NoError(t, err)
+	assert.False(t, resp.OK)
+}
@@ -1,22 +1,95 @@
 package logging
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
 const DefaultBufferSize = 1000
 
+// DefaultMaxLineLength is the longest line, in bytes, a buffer stores before
+// truncating it.
+const DefaultMaxLineLength = 8 * 1024
+
+// subscriberBuffer is how many lines a subscriber can fall behind before new
+// lines are dropped for it.
+const subscriberBuffer = 256
+
+// TruncatedMarker is appended to lines cut short by the line length limit.
+const TruncatedMarker = "…[truncated]"
+
+// DefaultTimestampFormat is the layout entries are formatted with until
+// SetTimestampFormat is called.
+const DefaultTimestampFormat = "15:04:05"
+
+var timestampFormat atomic.Pointer[string]
+
+// SetTimestampFormat sets the time layout used by Entry.Format for every
+// buffer. An empty layout restores DefaultTimestampFormat.
+func SetTimestampFormat(layout string) {
+	if layout == "" {
+		layout = DefaultTimestampFormat
+	}
+	timestampFormat.Store(&layout)
+}
+
+// TimestampFormat returns the layout set by SetTimestampFormat.
+func TimestampFormat() string {
+	if p := timestampFormat.Load(); p != nil {
+		return *p
+	}
+	return DefaultTimestampFormat
+}
+
+// Entry is a single log line. Time is zero for lines written without a
+// timestamp (see WriteString). Tag names the line's source, such as its
+// process, when the buffer has one (see SetTag).
+type Entry struct {
+	Time time.Time
+	Tag  string
+	Text string
+}
+
+// TaggedLine is a timestamped line paired with the tag of its source.
+type TaggedLine struct {
+	Tag  string
+	Line string
+}
+
+// Format renders the entry, prefixed with its bracketed timestamp (see
+// SetTimestampFormat) when withTime is true and the entry has one.
+func (e Entry) Format(withTime bool) string {
+	if !withTime || e.Time.IsZero() {
+		return e.Text
+	}
+	return fmt.Sprintf("[%s] %s", e.Time.Format(TimestampFormat()), e.Text)
+}
+
+// String renders the entry with its timestamp.
+func (e Entry) String() string {
+	return e.Format(true)
+}
+
 // RingBuffer is a thread-safe circular buffer for log lines.
 type RingBuffer struct {
-	mu    sync.Mutex
-	lines []string
-	size  int
-	pos   int
-	count int
+	mu      sync.Mutex
+	entries []Entry
+	size    int
+	pos     int
+	count   int
+	written uint64 // total entries ever appended
+
+	bytes    int // total length of the stored lines
+	maxBytes int // evict oldest lines beyond this many bytes; 0 means no limit
+	maxLine  int // truncate lines longer than this many bytes
+
+	tag string // stored on each new entry; see SetTag
+
+	subs map[chan string]struct{}
 }
 
 // NewRingBuffer creates a ring buffer with the given capacity.
@@ -25,38 +98,193 @@ func NewRingBuffer(size int) *RingBuffer {
 		size = DefaultBufferSize
 	}
 	return &RingBuffer{
-		lines: make([]string, size),
-		size:  size,
+		entries: make([]Entry, size),
+		size:    size,
+		maxLine: DefaultMaxLineLength,
+	}
+}
+
+// SetMaxLineLength sets the longest line, in bytes, stored in full. Longer
+// lines are cut and end in TruncatedMarker. n <= 0 restores
+// DefaultMaxLineLength.
+func (rb *RingBuffer) SetMaxLineLength(n int) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if n <= 0 {
+		n = DefaultMaxLineLength
+	}
+	rb.maxLine = n
+}
+
+// SetMaxBytes sets a budget for the total length of stored lines. Once it is
+// exceeded the oldest lines are evicted, even if the buffer holds fewer lines
+// than its capacity; the newest line is always kept. n <= 0 removes the
+// budget.
+func (rb *RingBuffer) SetMaxBytes(n int) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if n < 0 {
+		n = 0
 	}
+	rb.maxBytes = n
+	rb.evict()
 }
 
-// WriteString appends a line to the buffer.
+// SetTag sets the source tag stored on lines appended from now on, so they
+// can still be told apart once merged with other buffers' lines.
+func (rb *RingBuffer) SetTag(tag string) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.tag = tag
+}
+
+// WriteString appends a line to the buffer without a timestamp.
 func (rb *RingBuffer) WriteString(line string) {
+	rb.append(Entry{Text: line})
+}
+
+// Write implements io.Writer. It splits input on newlines and timestamps each
+// line. Lines of any length are accepted; see SetMaxLineLength.
+func (rb *RingBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	now := time.Now()
+	for len(p) > 0 {
+		line := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line, p = p[:i], p[i+1:]
+		} else {
+			p = nil
+		}
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		rb.append(Entry{Time: now, Text: string(line)})
+	}
+	return n, nil
+}
+
+func (rb *RingBuffer) append(e Entry) {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
-	rb.lines[rb.pos] = line
+	e.Text = truncateLine(e.Text, rb.maxLine)
+	e.Tag = rb.tag
+	if rb.count == rb.size {
+		rb.bytes -= len(rb.entries[rb.pos].Text)
+	}
+	rb.entries[rb.pos] = e
+	rb.bytes += len(e.Text)
 	rb.pos = (rb.pos + 1) % rb.size
 	if rb.count < rb.size {
 		rb.count++
 	}
+	rb.written++
+	rb.evict()
+
+	line := e.String()
+	for ch := range rb.subs {
+		select {
+		case ch <- line:
+		default: // a slow subscriber misses lines rather than stalling writers
+		}
+	}
 }
 
-// Write implements io.Writer. It splits input on newlines and timestamps each line.
-func (rb *RingBuffer) Write(p []byte) (int, error) {
-	scanner := bufio.NewScanner(bytes.NewReader(p))
-	for scanner.Scan() {
-		line := scanner.Text()
-		ts := time.Now().Format("15:04:05")
-		rb.WriteString(fmt.Sprintf("[%s] %s", ts, line))
+// Subscribe returns a channel that receives each line, timestamped, as it is
+// appended, and a function that ends the subscription and closes the
+// channel. A subscriber that falls more than a few hundred lines behind
+// misses lines; use Since to catch up.
+func (rb *RingBuffer) Subscribe() (<-chan string, func()) {
+	ch := make(chan string, subscriberBuffer)
+	rb.mu.Lock()
+	if rb.subs == nil {
+		rb.subs = make(map[chan string]struct{})
+	}
+	rb.subs[ch] = struct{}{}
+	rb.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			rb.mu.Lock()
+			delete(rb.subs, ch)
+			close(ch)
+			rb.mu.Unlock()
+		})
 	}
-	return len(p), nil
 }
 
-// Lines returns the last n lines. If n <= 0 or n > count, returns all lines.
-func (rb *RingBuffer) Lines(n int) []string {
+// truncateLine cuts s to at most max bytes, on a UTF-8 boundary, and marks
+// it as truncated.
+func truncateLine(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + TruncatedMarker
+}
+
+// evict drops the oldest entries until the buffer is within its byte budget,
+// keeping at least the newest one. The caller must hold rb.mu.
+func (rb *RingBuffer) evict() {
+	if rb.maxBytes == 0 {
+		return
+	}
+	for rb.bytes > rb.maxBytes && rb.count > 1 {
+		oldest := (rb.pos - rb.count + rb.size) % rb.size
+		rb.bytes -= len(rb.entries[oldest].Text)
+		rb.entries[oldest] = Entry{}
+		rb.count--
+	}
+}
+
+// Clear drops every stored line. The sequence number keeps counting, so
+// followers using Since only see lines written afterwards.
+func (rb *RingBuffer) Clear() {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	clear(rb.entries)
+	rb.pos = 0
+	rb.count = 0
+	rb.bytes = 0
+}
+
+// Entries returns the last n entries. If n <= 0 or n > count, returns all entries.
+func (rb *RingBuffer) Entries(n int) []Entry {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	return rb.last(n)
+}
+
+// Since returns the entries appended after the buffer's sequence number
+// reached seq, along with the current sequence number to pass to the next
+// call. Entries that have already been overwritten are skipped. Since(0)
+// returns everything in the buffer.
+func (rb *RingBuffer) Since(seq uint64) ([]Entry, uint64) {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
 
+	if seq >= rb.written {
+		return nil, rb.written
+	}
+	n := rb.written - seq
+	if n > uint64(rb.count) {
+		n = uint64(rb.count)
+	}
+	return rb.last(int(n)), rb.written
+}
+
+// Tail is like Entries but also returns the current sequence number, so a
+// caller can follow up with Since without missing or repeating entries.
+func (rb *RingBuffer) Tail(n int) ([]Entry, uint64) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	return rb.last(n), rb.written
+}
+
+// last returns the last n entries, or all of them if n <= 0. The caller
+// must hold rb.mu.
+func (rb *RingBuffer) last(n int) []Entry {
 	if n <= 0 || n > rb.count {
 		n = rb.count
 	}
@@ -64,10 +292,24 @@ func (rb *RingBuffer) Lines(n int) []string {
 		return nil
 	}
 
-	result := make([]string, n)
+	result := make([]Entry, n)
 	start := (rb.pos - n + rb.size) % rb.size
 	for i := 0; i < n; i++ {
-		result[i] = rb.lines[(start+i)%rb.size]
+		result[i] = rb.entries[(start+i)%rb.size]
+	}
+	return result
+}
+
+// Lines returns the last n lines, timestamped. If n <= 0 or n > count,
+// returns all lines.
+func (rb *RingBuffer) Lines(n int) []string {
+	entries := rb.Entries(n)
+	if entries == nil {
+		return nil
+	}
+	result := make([]string, len(entries))
+	for i, e := range entries {
+		result[i] = e.String()
 	}
 	return result
 }
@@ -77,6 +319,20 @@ func (rb *RingBuffer) All() []string {
 	return rb.Lines(0)
 }
 
+// AllTagged is like All but pairs each line with the tag it was written
+// under.
+func (rb *RingBuffer) AllTagged() []TaggedLine {
+	entries := rb.Entries(0)
+	if entries == nil {
+		return nil
+	}
+	result := make([]TaggedLine, len(entries))
+	for i, e := range entries {
+		result[i] = TaggedLine{Tag: e.Tag, Line: e.String()}
+	}
+	return result
+}
+
 // Len returns the number of lines currently in the buffer.
 func (rb *RingBuffer) Len() int {
 	rb.mu.Lock()
@@ -2,10 +2,15 @@ package logging
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRingBuffer_WriteAndRead(t *testing.T) {
@@ -90,3 +95,226 @@ func TestRingBuffer_ThreadSafety(t *testing.T) {
 	lines := rb.All()
 	assert.Equal(t, 100, len(lines))
 }
+
+func TestRingBuffer_EntriesKeepTimestampSeparate(t *testing.T) {
+	rb := NewRingBuffer(10)
+	rb.WriteString("plain")
+	rb.Write([]byte("stamped\n"))
+
+	entries := rb.Entries(0)
+	assert.Len(t, entries, 2)
+	assert.True(t, entries[0].Time.IsZero())
+	assert.False(t, entries[1].Time.IsZero())
+	assert.Equal(t, "stamped", entries[1].Text)
+
+	assert.Equal(t, "stamped", entries[1].Format(false))
+	assert.Equal(t, "["+entries[1].Time.Format("15:04:05")+"] stamped", entries[1].Format(true))
+	assert.Equal(t, "plain", entries[0].Format(true))
+}
+
+func TestRingBuffer_AllTagged(t *testing.T) {
+	rb := NewRingBuffer(10)
+	rb.WriteString("before")
+	rb.SetTag("bastion")
+	rb.WriteString("after")
+
+	lines := rb.AllTagged()
+	require.Len(t, lines, 2)
+	assert.Equal(t, TaggedLine{Tag: "", Line: "before"}, lines[0])
+	assert.Equal(t, TaggedLine{Tag: "bastion", Line: "after"}, lines[1])
+	assert.Equal(t, "bastion", rb.Entries(1)[0].Tag)
+}
+
+func TestRingBuffer_Clear(t *testing.T) {
+	rb := NewRingBuffer(3)
+	rb.SetMaxBytes(100)
+	rb.WriteString("one")
+	rb.WriteString("two")
+	_, seq := rb.Tail(0)
+
+	rb.Clear()
+	assert.Equal(t, 0, rb.Len())
+	assert.Nil(t, rb.All())
+	entries, next := rb.Since(0)
+	assert.Empty(t, entries)
+	assert.Equal(t, seq, next)
+
+	rb.WriteString("three")
+	entries, _ = rb.Since(seq)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "three", entries[0].Text)
+	assert.Equal(t, []string{"three"}, rb.All())
+}
+
+func TestRingBuffer_Subscribe(t *testing.T) {
+	rb := NewRingBuffer(10)
+	rb.WriteString("before")
+
+	lines, unsubscribe := rb.Subscribe()
+	rb.WriteString("one")
+	rb.WriteString("two")
+	assert.Equal(t, "one", <-lines)
+	assert.Equal(t, "two", <-lines)
+
+	unsubscribe()
+	unsubscribe()
+	rb.WriteString("after")
+	_, ok := <-lines
+	assert.False(t, ok)
+}
+
+func TestRingBuffer_SubscribeDoesNotBlockWriters(t *testing.T) {
+	rb := NewRingBuffer(10)
+	lines, unsubscribe := rb.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		rb.WriteString(fmt.Sprintf("line %d", i))
+	}
+	assert.Len(t, lines, subscriberBuffer)
+	assert.Equal(t, "line 0", <-lines)
+}
+
+func TestEntry_FormatUsesTimestampFormat(t *testing.T) {
+	defer SetTimestampFormat("")
+	e := Entry{Time: time.Date(2024, 3, 9, 14, 5, 7, 250e6, time.UTC), Text: "hi"}
+
+	assert.Equal(t, "[14:05:07] hi", e.Format(true))
+
+	SetTimestampFormat("2006-01-02 15:04:05.000")
+	assert.Equal(t, "[2024-03-09 14:05:07.250] hi", e.Format(true))
+
+	SetTimestampFormat("")
+	assert.Equal(t, DefaultTimestampFormat, TimestampFormat())
+}
+
+func TestRingBuffer_Since(t *testing.T) {
+	rb := NewRingBuffer(3)
+
+	entries, seq := rb.Since(0)
+	assert.Nil(t, entries)
+	assert.Equal(t, uint64(0), seq)
+
+	rb.WriteString("1")
+	rb.WriteString("2")
+	entries, seq = rb.Since(0)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, uint64(2), seq)
+
+	entries, seq = rb.Since(seq)
+	assert.Nil(t, entries)
+	assert.Equal(t, uint64(2), seq)
+
+	// Overwritten entries are skipped.
+	for _, l := range []string{"3", "4", "5", "6"} {
+		rb.WriteString(l)
+	}
+	entries, seq = rb.Since(2)
+	assert.Equal(t, "4", entries[0].Text)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, uint64(6), seq)
+}
+
+func TestRingBuffer_MaxBytes(t *testing.T) {
+	rb := NewRingBuffer(10)
+	rb.SetMaxBytes(10)
+
+	rb.WriteString("aaaa")
+	rb.WriteString("bbbb")
+	assert.Equal(t, []string{"aaaa", "bbbb"}, rb.All())
+
+	// Over budget: the oldest line goes even though the line cap isn't hit.
+	rb.WriteString("cccc")
+	assert.Equal(t, []string{"bbbb", "cccc"}, rb.All())
+
+	// A single line over budget is kept on its own.
+	rb.WriteString("dddddddddddddddd")
+	assert.Equal(t, []string{"dddddddddddddddd"}, rb.All())
+
+	// The line cap still applies under the byte budget.
+	rb = NewRingBuffer(2)
+	rb.SetMaxBytes(100)
+	for _, l := range []string{"1", "2", "3"} {
+		rb.WriteString(l)
+	}
+	assert.Equal(t, []string{"2", "3"}, rb.All())
+
+	// Lowering the budget evicts straight away.
+	rb.SetMaxBytes(1)
+	assert.Equal(t, []string{"3"}, rb.All())
+}
+
+func TestRingBuffer_MaxLineLength(t *testing.T) {
+	rb := NewRingBuffer(10)
+	rb.SetMaxLineLength(4)
+
+	rb.WriteString("abcd")
+	rb.WriteString("abcdef")
+	// Cuts back to a rune boundary rather than splitting "é".
+	rb.WriteString("abcé")
+
+	assert.Equal(t, []string{"abcd", "abcd" + TruncatedMarker, "abc" + TruncatedMarker}, rb.All())
+
+	rb = NewRingBuffer(10)
+	rb.WriteString(strings.Repeat("x", DefaultMaxLineLength+1))
+	assert.Len(t, rb.All()[0], DefaultMaxLineLength+len(TruncatedMarker))
+}
+
+func TestRingBuffer_WriteLongLine(t *testing.T) {
+	rb := NewRingBuffer(10)
+	rb.SetMaxLineLength(1 << 20)
+
+	long := strings.Repeat("x", 100*1024)
+	rb.Write([]byte(long + "\r\nnext\n"))
+
+	entries := rb.Entries(0)
+	require.Len(t, entries, 2)
+	assert.Equal(t, long, entries[0].Text)
+	assert.Equal(t, "next", entries[1].Text)
+}
+
+func TestReadTail(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	// Larger than one read chunk, so the tail spans chunks.
+	var big strings.Builder
+	for i := range 20000 {
+		fmt.Fprintf(&big, "line %d\n", i)
+	}
+	lines, err := ReadTail(write("big.log", big.String()), 3, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 19997", "line 19998", "line 19999"}, lines)
+
+	// The byte cap stops the read early and drops the line it cuts through.
+	lines, err = ReadTail(write("capped.log", "first\n"+strings.Repeat("x", 100)+"\nlast\n"), 5, 50)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"last"}, lines)
+
+	lines, err = ReadTail(write("short.log", "a\r\nb\r\nunterminated"), 5, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "unterminated"}, lines)
+
+	lines, err = ReadTail(write("empty.log", ""), 5, 1<<20)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+
+	lines, err = ReadTail(filepath.Join(dir, "missing.log"), 5, 1<<20)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestRingBuffer_LoadTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0o644))
+
+	rb := NewRingBuffer(2)
+	n, err := rb.LoadTail(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"two", "three"}, rb.All())
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// tailChunk is how much of a file ReadTail reads at a time, working back
+// from the end.
+const tailChunk = 64 * 1024
+
+// ReadTail returns the last n lines of the file at path, oldest first,
+// reading at most maxBytes from the end of the file so that a log with
+// huge lines stays cheap to load. A line cut off by maxBytes is dropped. A
+// missing or empty file yields no lines and no error.
+func ReadTail(path string, n int, maxBytes int64) ([]string, error) {
+	if n <= 0 || maxBytes <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read backwards until the chunks hold more than n line breaks, which
+	// guarantees n complete lines even when the last one is unterminated.
+	end := info.Size()
+	stop := max(end-maxBytes, 0)
+	var chunks [][]byte // last chunk first
+	breaks := 0
+	for end > stop && breaks <= n {
+		size := min(int64(tailChunk), end-stop)
+		end -= size
+		buf := make([]byte, size)
+		if _, err := f.ReadAt(buf, end); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, buf)
+		breaks += bytes.Count(buf, []byte{'\n'})
+	}
+	slices.Reverse(chunks)
+
+	text := strings.TrimSuffix(string(bytes.Join(chunks, nil)), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	lines := strings.Split(text, "\n")
+	if end > 0 {
+		// Reading started mid-file, so the first line is incomplete.
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines, nil
+}
+
+// LoadTail fills the buffer with up to its capacity of the last lines of the
+// file at path, without timestamps, and returns how many it loaded. It reads
+// no more than a buffer full of maximum-length lines. A missing or empty
+// file loads nothing.
+func (rb *RingBuffer) LoadTail(path string) (int, error) {
+	rb.mu.Lock()
+	n, maxLine := rb.size, rb.maxLine
+	rb.mu.Unlock()
+
+	lines, err := ReadTail(path, n, int64(n)*int64(maxLine+1))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range lines {
+		rb.WriteString(line)
+	}
+	return len(lines), nil
+}
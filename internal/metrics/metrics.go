@@ -0,0 +1,86 @@
+// Package metrics serves process state in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/frontendtony/shepherd/internal/process"
+)
+
+// metric describes a single per-process metric family.
+type metric struct {
+	name  string
+	help  string
+	kind  string // "gauge" or "counter"
+	value func(process.ProcessState) float64
+}
+
+var metrics = []metric{
+	{
+		name: "shepherd_process_up",
+		help: "Whether the process is running (1) or not (0).",
+		kind: "gauge",
+		value: func(s process.ProcessState) float64 {
+			if s.Status.IsRunning() {
+				return 1
+			}
+			return 0
+		},
+	},
+	{
+		name:  "shepherd_process_restarts_total",
+		help:  "Number of times the process has been restarted.",
+		kind:  "counter",
+		value: func(s process.ProcessState) float64 { return float64(s.Restarts) },
+	},
+	{
+		name:  "shepherd_process_uptime_seconds",
+		help:  "Seconds since the process was last started, or its final run time if stopped.",
+		kind:  "gauge",
+		value: func(s process.ProcessState) float64 { return s.Uptime().Seconds() },
+	},
+	{
+		name:  "shepherd_process_retry_count",
+		help:  "Current consecutive retry attempt for the process.",
+		kind:  "gauge",
+		value: func(s process.ProcessState) float64 { return float64(s.RetryCount) },
+	},
+	{
+		name:  "shepherd_process_memory_bytes",
+		help:  "Resident memory of the process group in bytes.",
+		kind:  "gauge",
+		value: func(s process.ProcessState) float64 { return float64(s.MemoryBytes) },
+	},
+}
+
+// Handler returns an http.Handler that renders metrics for every process on
+// each scrape.
+func Handler(mgr *process.ProcessManager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w, mgr.GetAllStates())
+	})
+}
+
+// Write renders states in the Prometheus text exposition format.
+func Write(w io.Writer, states []process.ProcessState) {
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+		for _, s := range states {
+			fmt.Fprintf(w, "%s{name=\"%s\"} %g\n", m.name, escapeLabel(s.Name), m.value(s))
+		}
+	}
+}
+
+func escapeLabel(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return strings.ReplaceAll(v, "\n", `\n`)
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	states := []process.ProcessState{
+		{Name: "web", Status: process.StatusStopped, Restarts: 2},
+		{Name: "db", Status: process.StatusHealthy, StartedAt: time.Now().Add(-time.Minute), Restarts: 1},
+	}
+
+	var buf bytes.Buffer
+	Write(&buf, states)
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE shepherd_process_up gauge\n")
+	assert.Contains(t, out, `shepherd_process_up{name="db"} 1`)
+	assert.Contains(t, out, `shepherd_process_up{name="web"} 0`)
+	assert.Contains(t, out, "# TYPE shepherd_process_restarts_total counter\n")
+	assert.Contains(t, out, `shepherd_process_restarts_total{name="web"} 2`)
+	assert.Regexp(t, `shepherd_process_uptime_seconds\{name="db"\} 60(\.\d+)?\n`, out)
+
+	// Series are sorted by process name.
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte(`up{name="db"}`)), bytes.Index(buf.Bytes(), []byte(`up{name="web"}`)))
+}
+
+func TestEscapeLabel(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\nd`, escapeLabel("a\"b\\c\nd"))
+}
@@ -0,0 +1,70 @@
+// Package notify shows desktop notifications when processes fail for good.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"github.com/frontendtony/shepherd/internal/process"
+)
+
+// Notifier turns terminal failures into desktop notifications.
+type Notifier struct {
+	// send displays a notification; tests replace it.
+	send func(title, body string) error
+}
+
+// New returns a Notifier that uses notify-send on Linux and osascript on
+// macOS. It returns an error on other platforms.
+func New() (*Notifier, error) {
+	switch runtime.GOOS {
+	case "linux", "darwin":
+		return &Notifier{send: desktopNotify}, nil
+	}
+	return nil, fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+}
+
+// Run sends a notification for each event that leaves a process failed, until
+// events is closed or ctx is done. Failures that will be retried don't reach
+// StatusFailed, so only crashes that exhausted their retries, failed
+// dependencies, and failed starts are reported.
+func (n *Notifier) Run(ctx context.Context, events <-chan process.StateEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.NewState != process.StatusFailed {
+				continue
+			}
+			body := ev.Error
+			if body == "" {
+				body = "process failed"
+			}
+			if err := n.send("shepherd: "+ev.Name+" failed", body); err != nil {
+				slog.Warn("desktop notification failed", "process", ev.Name, "error", err)
+			}
+		}
+	}
+}
+
+func desktopNotify(title, body string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(body), strconv.Quote(title))
+		cmd = exec.Command("osascript", "-e", script)
+	} else {
+		cmd = exec.Command("notify-send", "--urgency=critical", title, body)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Path, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifier_OnlyFailures(t *testing.T) {
+	type sent struct{ title, body string }
+	var got []sent
+	n := &Notifier{send: func(title, body string) error {
+		got = append(got, sent{title, body})
+		return nil
+	}}
+
+	events := make(chan process.StateEvent, 4)
+	events <- process.StateEvent{Name: "api", OldState: process.StatusRunning, NewState: process.StatusRetrying}
+	events <- process.StateEvent{Name: "api", OldState: process.StatusFailed, NewState: process.StatusFailed, Error: "max retries exhausted (exit code 1)"}
+	events <- process.StateEvent{Name: "web", OldState: process.StatusStarting, NewState: process.StatusFailed}
+	events <- process.StateEvent{Name: "db", OldState: process.StatusRunning, NewState: process.StatusStopped}
+	close(events)
+
+	n.Run(context.Background(), events)
+
+	assert.Equal(t, []sent{
+		{"shepherd: api failed", "max retries exhausted (exit code 1)"},
+		{"shepherd: web failed", "process failed"},
+	}, got)
+}
@@ -2,6 +2,7 @@ package process
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/frontendtony/shepherd/internal/config"
@@ -13,6 +14,13 @@ type DependencyGraph struct {
 	forward map[string][]string
 	// reverse: process -> processes that depend on it
 	reverse map[string][]string
+	// optional: process -> dependencies whose failure it tolerates
+	optional map[string]map[string]bool
+	// after: process -> processes it starts after because its group depends
+	// on theirs; before is the reverse. These only order processes that are
+	// started together and, unlike forward, don't pull anything in.
+	after  map[string][]string
+	before map[string][]string
 	// all known process names
 	nodes map[string]bool
 }
@@ -20,43 +28,85 @@ type DependencyGraph struct {
 // NewDependencyGraph builds a dependency graph from config.
 func NewDependencyGraph(cfg *config.Config) *DependencyGraph {
 	g := &DependencyGraph{
-		forward: make(map[string][]string),
-		reverse: make(map[string][]string),
-		nodes:   make(map[string]bool),
+		forward:  make(map[string][]string),
+		reverse:  make(map[string][]string),
+		optional: make(map[string]map[string]bool),
+		after:    config.GroupOrdering(cfg),
+		before:   make(map[string][]string),
+		nodes:    make(map[string]bool),
 	}
 
 	for name, proc := range cfg.Processes {
 		g.nodes[name] = true
-		g.forward[name] = proc.DependsOn
+		g.forward[name] = proc.DependencyNames()
 		for _, dep := range proc.DependsOn {
-			g.reverse[dep] = append(g.reverse[dep], name)
+			g.reverse[dep.Name] = append(g.reverse[dep.Name], name)
+			if dep.Optional {
+				if g.optional[name] == nil {
+					g.optional[name] = make(map[string]bool)
+				}
+				g.optional[name][dep.Name] = true
+			}
+		}
+	}
+	for name, after := range g.after {
+		for _, other := range after {
+			g.before[other] = append(g.before[other], name)
 		}
 	}
 
 	return g
 }
 
-// Validate checks for cycles using Kahn's algorithm.
+// Validate checks for cycles, reporting one as a path such as "a -> b -> a".
 func (g *DependencyGraph) Validate() error {
-	inDegree := make(map[string]int)
+	deps := make(map[string][]string, len(g.nodes))
 	for name := range g.nodes {
-		inDegree[name] = len(g.forward[name])
+		for _, dep := range g.forward[name] {
+			if g.nodes[dep] {
+				deps[name] = append(deps[name], dep)
+			}
+		}
+		deps[name] = append(deps[name], g.after[name]...)
+	}
+	if cycle := config.FindCycle(deps); cycle != nil {
+		return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// StartOrder returns a topological ordering of the given targets and all their
+// transitive dependencies. Dependencies come first in the returned slice, and
+// ties are broken by name so the order is deterministic.
+func (g *DependencyGraph) StartOrder(targets []string) ([]string, error) {
+	required, err := g.collectRequired(targets)
+	if err != nil {
+		return nil, err
 	}
 
+	// Topological sort of required nodes using Kahn's algorithm.
+	inDegree := g.requiredInDegree(required)
+
+	// Independent nodes are taken in name order, so the result is the same
+	// from run to run.
 	var queue []string
 	for name, deg := range inDegree {
 		if deg == 0 {
 			queue = append(queue, name)
 		}
 	}
+	sort.Strings(queue)
 
-	visited := 0
+	var order []string
 	for len(queue) > 0 {
 		node := queue[0]
 		queue = queue[1:]
-		visited++
+		order = append(order, node)
 
-		for _, dep := range g.reverse[node] {
+		for _, dep := range g.successors(node) {
+			if !required[dep] {
+				continue
+			}
 			inDegree[dep]--
 			if inDegree[dep] == 0 {
 				queue = append(queue, dep)
@@ -64,22 +114,63 @@ func (g *DependencyGraph) Validate() error {
 		}
 	}
 
-	if visited != len(g.nodes) {
-		var cycleNodes []string
-		for name, deg := range inDegree {
-			if deg > 0 {
-				cycleNodes = append(cycleNodes, name)
+	if len(order) != len(required) {
+		return nil, fmt.Errorf("dependency cycle detected")
+	}
+
+	return order, nil
+}
+
+// StartLevels groups the given targets and all their transitive dependencies
+// into topological levels. Every process in a level depends only on processes
+// in earlier levels, so the processes within a level can start in parallel.
+// Each level is sorted by name.
+func (g *DependencyGraph) StartLevels(targets []string) ([][]string, error) {
+	required, err := g.collectRequired(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	inDegree := g.requiredInDegree(required)
+
+	var current []string
+	for name, deg := range inDegree {
+		if deg == 0 {
+			current = append(current, name)
+		}
+	}
+
+	var levels [][]string
+	placed := 0
+	for len(current) > 0 {
+		sort.Strings(current)
+		levels = append(levels, current)
+		placed += len(current)
+
+		var next []string
+		for _, node := range current {
+			for _, dep := range g.successors(node) {
+				if !required[dep] {
+					continue
+				}
+				inDegree[dep]--
+				if inDegree[dep] == 0 {
+					next = append(next, dep)
+				}
 			}
 		}
-		return fmt.Errorf("dependency cycle detected involving: %s", strings.Join(cycleNodes, ", "))
+		current = next
 	}
-	return nil
+
+	if placed != len(required) {
+		return nil, fmt.Errorf("dependency cycle detected")
+	}
+
+	return levels, nil
 }
 
-// StartOrder returns a topological ordering of the given targets and all their
-// transitive dependencies. Dependencies come first in the returned slice.
-func (g *DependencyGraph) StartOrder(targets []string) ([]string, error) {
-	// Collect all required nodes (targets + transitive deps).
+// collectRequired returns the set of targets plus all their transitive dependencies.
+func (g *DependencyGraph) collectRequired(targets []string) (map[string]bool, error) {
 	required := make(map[string]bool)
 	var collectDeps func(name string)
 	collectDeps = func(name string) {
@@ -97,48 +188,42 @@ func (g *DependencyGraph) StartOrder(targets []string) ([]string, error) {
 		}
 		collectDeps(t)
 	}
+	return required, nil
+}
 
-	// Topological sort of required nodes using Kahn's algorithm.
+// requiredInDegree counts, for each required node, how many of its direct
+// dependencies, and of the processes it starts after, are also required.
+func (g *DependencyGraph) requiredInDegree(required map[string]bool) map[string]int {
 	inDegree := make(map[string]int)
 	for name := range required {
 		count := 0
-		for _, dep := range g.forward[name] {
+		for _, dep := range g.predecessors(name) {
 			if required[dep] {
 				count++
 			}
 		}
 		inDegree[name] = count
 	}
+	return inDegree
+}
 
-	var queue []string
-	for name, deg := range inDegree {
-		if deg == 0 {
-			queue = append(queue, name)
-		}
-	}
-
-	var order []string
-	for len(queue) > 0 {
-		node := queue[0]
-		queue = queue[1:]
-		order = append(order, node)
-
-		for _, dep := range g.reverse[node] {
-			if !required[dep] {
-				continue
-			}
-			inDegree[dep]--
-			if inDegree[dep] == 0 {
-				queue = append(queue, dep)
-			}
-		}
-	}
+// predecessors returns the processes that must come before name in a start
+// order: its dependencies and the processes it starts after.
+func (g *DependencyGraph) predecessors(name string) []string {
+	return append(append([]string(nil), g.forward[name]...), g.after[name]...)
+}
 
-	if len(order) != len(required) {
-		return nil, fmt.Errorf("dependency cycle detected")
-	}
+// successors is the reverse of predecessors, sorted by name.
+func (g *DependencyGraph) successors(name string) []string {
+	next := append(append([]string(nil), g.reverse[name]...), g.before[name]...)
+	sort.Strings(next)
+	return next
+}
 
-	return order, nil
+// After returns the processes that name starts after because its group
+// depends on theirs.
+func (g *DependencyGraph) After(name string) []string {
+	return g.after[name]
 }
 
 // StopOrder returns the reverse of StartOrder — dependents come first
@@ -196,3 +281,120 @@ func (g *DependencyGraph) Dependencies(name string) []string {
 	walk(name)
 	return result
 }
+
+// IsOptional reports whether name's dependency on dep is optional.
+func (g *DependencyGraph) IsOptional(name, dep string) bool {
+	return g.optional[name][dep]
+}
+
+// RequiredDependents is like Dependents but only follows non-optional edges:
+// it returns the processes that cannot run if name fails.
+func (g *DependencyGraph) RequiredDependents(name string) []string {
+	visited := make(map[string]bool)
+	var result []string
+
+	var walk func(n string)
+	walk = func(n string) {
+		for _, dep := range g.reverse[n] {
+			if !visited[dep] && !g.optional[dep][n] {
+				visited[dep] = true
+				result = append(result, dep)
+				walk(dep)
+			}
+		}
+	}
+
+	walk(name)
+	return result
+}
+
+// RequiredDependencies is like Dependencies but only follows non-optional
+// edges: it returns the processes whose failure prevents name from starting.
+func (g *DependencyGraph) RequiredDependencies(name string) []string {
+	visited := make(map[string]bool)
+	var result []string
+
+	var walk func(n string)
+	walk = func(n string) {
+		for _, dep := range g.forward[n] {
+			if !visited[dep] && !g.optional[n][dep] {
+				visited[dep] = true
+				result = append(result, dep)
+				walk(dep)
+			}
+		}
+	}
+
+	walk(name)
+	return result
+}
+
+// DOT renders the graph in Graphviz DOT format. Each edge points from a
+// process to one of its dependencies; optional dependencies are dashed.
+// Processes are clustered by group, and a process listed in several groups is
+// drawn in the first by name.
+func (g *DependencyGraph) DOT(groups map[string]config.Group) string {
+	groupNames := make([]string, 0, len(groups))
+	for name := range groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+
+	placed := make(map[string]bool)
+	var b strings.Builder
+	b.WriteString("digraph shepherd {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for i, group := range groupNames {
+		var members []string
+		for _, name := range groups[group].Processes {
+			if g.nodes[name] && !placed[name] {
+				placed[name] = true
+				members = append(members, name)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(group))
+		for _, name := range members {
+			fmt.Fprintf(&b, "    %s;\n", dotQuote(name))
+		}
+		b.WriteString("  }\n")
+	}
+
+	names := make([]string, 0, len(g.nodes))
+	for name := range g.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !placed[name] {
+			fmt.Fprintf(&b, "  %s;\n", dotQuote(name))
+		}
+	}
+
+	for _, name := range names {
+		deps := append([]string(nil), g.forward[name]...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			fmt.Fprintf(&b, "  %s -> %s", dotQuote(name), dotQuote(dep))
+			if g.optional[name][dep] {
+				b.WriteString(" [style=dashed]")
+			}
+			b.WriteString(";\n")
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
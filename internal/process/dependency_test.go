@@ -17,8 +17,8 @@ func buildGraph(procs map[string]config.Process) *DependencyGraph {
 func TestDependencyGraph_LinearChain(t *testing.T) {
 	// C <- B <- A (A depends on B, B depends on C)
 	g := buildGraph(map[string]config.Process{
-		"A": {Command: "a", DependsOn: []string{"B"}},
-		"B": {Command: "b", DependsOn: []string{"C"}},
+		"A": {Command: "a", DependsOn: []config.Dependency{{Name: "B"}}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "C"}}},
 		"C": {Command: "c"},
 	})
 
@@ -33,9 +33,9 @@ func TestDependencyGraph_Diamond(t *testing.T) {
 	// D depends on B and C, both depend on A
 	g := buildGraph(map[string]config.Process{
 		"A": {Command: "a"},
-		"B": {Command: "b", DependsOn: []string{"A"}},
-		"C": {Command: "c", DependsOn: []string{"A"}},
-		"D": {Command: "d", DependsOn: []string{"B", "C"}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
+		"C": {Command: "c", DependsOn: []config.Dependency{{Name: "A"}}},
+		"D": {Command: "d", DependsOn: []config.Dependency{{Name: "B"}, {Name: "C"}}},
 	})
 
 	require.NoError(t, g.Validate())
@@ -43,34 +43,50 @@ func TestDependencyGraph_Diamond(t *testing.T) {
 	order, err := g.StartOrder([]string{"D"})
 	require.NoError(t, err)
 
-	// A must be first, D must be last. B and C can be in any order.
-	assert.Equal(t, "A", order[0])
-	assert.Equal(t, "D", order[3])
-	middle := order[1:3]
-	sort.Strings(middle)
-	assert.Equal(t, []string{"B", "C"}, middle)
+	// A must be first, D must be last. B and C are independent, so they
+	// come in name order.
+	assert.Equal(t, []string{"A", "B", "C", "D"}, order)
+}
+
+func TestDependencyGraph_StartOrder_Deterministic(t *testing.T) {
+	g := buildGraph(map[string]config.Process{
+		"web":    {Command: "web", DependsOn: []config.Dependency{{Name: "api"}}},
+		"api":    {Command: "api", DependsOn: []config.Dependency{{Name: "db"}, {Name: "cache"}}},
+		"worker": {Command: "worker", DependsOn: []config.Dependency{{Name: "db"}}},
+		"db":     {Command: "db"},
+		"cache":  {Command: "cache"},
+		"docs":   {Command: "docs"},
+	})
+	targets := []string{"web", "worker", "docs"}
+
+	want := []string{"cache", "db", "docs", "api", "worker", "web"}
+	for i := 0; i < 20; i++ {
+		order, err := g.StartOrder(targets)
+		require.NoError(t, err)
+		assert.Equal(t, want, order)
+	}
 }
 
 func TestDependencyGraph_CycleDetected(t *testing.T) {
 	g := buildGraph(map[string]config.Process{
-		"A": {Command: "a", DependsOn: []string{"C"}},
-		"B": {Command: "b", DependsOn: []string{"A"}},
-		"C": {Command: "c", DependsOn: []string{"B"}},
+		"A": {Command: "a", DependsOn: []config.Dependency{{Name: "C"}}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
+		"C": {Command: "c", DependsOn: []config.Dependency{{Name: "B"}}},
 	})
 
 	err := g.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "dependency cycle detected")
+	assert.Contains(t, err.Error(), "dependency cycle detected: A -> C -> B -> A")
 }
 
 func TestDependencyGraph_SelfCycle(t *testing.T) {
 	g := buildGraph(map[string]config.Process{
-		"A": {Command: "a", DependsOn: []string{"A"}},
+		"A": {Command: "a", DependsOn: []config.Dependency{{Name: "A"}}},
 	})
 
 	err := g.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "dependency cycle detected")
+	assert.Contains(t, err.Error(), "dependency cycle detected: A -> A")
 }
 
 func TestDependencyGraph_NoDependencies(t *testing.T) {
@@ -96,9 +112,9 @@ func TestDependencyGraph_NoDependencies(t *testing.T) {
 func TestDependencyGraph_DisconnectedComponents(t *testing.T) {
 	// Two independent chains: A->B, C->D
 	g := buildGraph(map[string]config.Process{
-		"A": {Command: "a", DependsOn: []string{"B"}},
+		"A": {Command: "a", DependsOn: []config.Dependency{{Name: "B"}}},
 		"B": {Command: "b"},
-		"C": {Command: "c", DependsOn: []string{"D"}},
+		"C": {Command: "c", DependsOn: []config.Dependency{{Name: "D"}}},
 		"D": {Command: "d"},
 	})
 
@@ -119,7 +135,7 @@ func TestDependencyGraph_TargetSubset(t *testing.T) {
 	// B depends on A. Requesting just B should include A.
 	g := buildGraph(map[string]config.Process{
 		"A": {Command: "a"},
-		"B": {Command: "b", DependsOn: []string{"A"}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
 		"C": {Command: "c"},
 	})
 
@@ -142,8 +158,8 @@ func TestDependencyGraph_Dependents(t *testing.T) {
 	// B and C both depend on A
 	g := buildGraph(map[string]config.Process{
 		"A": {Command: "a"},
-		"B": {Command: "b", DependsOn: []string{"A"}},
-		"C": {Command: "c", DependsOn: []string{"A"}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
+		"C": {Command: "c", DependsOn: []config.Dependency{{Name: "A"}}},
 	})
 
 	deps := g.Dependents("A")
@@ -155,8 +171,8 @@ func TestDependencyGraph_Dependents_Transitive(t *testing.T) {
 	// C depends on B, B depends on A
 	g := buildGraph(map[string]config.Process{
 		"A": {Command: "a"},
-		"B": {Command: "b", DependsOn: []string{"A"}},
-		"C": {Command: "c", DependsOn: []string{"B"}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
+		"C": {Command: "c", DependsOn: []config.Dependency{{Name: "B"}}},
 	})
 
 	deps := g.Dependents("A")
@@ -168,8 +184,8 @@ func TestDependencyGraph_Dependencies(t *testing.T) {
 	// C depends on B, B depends on A
 	g := buildGraph(map[string]config.Process{
 		"A": {Command: "a"},
-		"B": {Command: "b", DependsOn: []string{"A"}},
-		"C": {Command: "c", DependsOn: []string{"B"}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
+		"C": {Command: "c", DependsOn: []config.Dependency{{Name: "B"}}},
 	})
 
 	deps := g.Dependencies("C")
@@ -189,8 +205,8 @@ func TestDependencyGraph_Dependencies_None(t *testing.T) {
 func TestDependencyGraph_StopOrder(t *testing.T) {
 	// C <- B <- A (start: C, B, A; stop: A, B, C)
 	g := buildGraph(map[string]config.Process{
-		"A": {Command: "a", DependsOn: []string{"B"}},
-		"B": {Command: "b", DependsOn: []string{"C"}},
+		"A": {Command: "a", DependsOn: []config.Dependency{{Name: "B"}}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "C"}}},
 		"C": {Command: "c"},
 	})
 
@@ -202,7 +218,7 @@ func TestDependencyGraph_StopOrder(t *testing.T) {
 func TestDependencyGraph_Dependents_LeafNode(t *testing.T) {
 	g := buildGraph(map[string]config.Process{
 		"A": {Command: "a"},
-		"B": {Command: "b", DependsOn: []string{"A"}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
 	})
 
 	deps := g.Dependents("B")
@@ -222,8 +238,112 @@ func TestDependencyGraph_StartOrder_SingleNode(t *testing.T) {
 func TestDependencyGraph_Validate_NoCycle(t *testing.T) {
 	g := buildGraph(map[string]config.Process{
 		"A": {Command: "a"},
-		"B": {Command: "b", DependsOn: []string{"A"}},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
 	})
 
 	assert.NoError(t, g.Validate())
 }
+
+func TestDependencyGraph_StartLevels_Diamond(t *testing.T) {
+	g := buildGraph(map[string]config.Process{
+		"A": {Command: "a"},
+		"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A"}}},
+		"C": {Command: "c", DependsOn: []config.Dependency{{Name: "A"}}},
+		"D": {Command: "d", DependsOn: []config.Dependency{{Name: "B"}, {Name: "C"}}},
+		"E": {Command: "e"},
+	})
+
+	levels, err := g.StartLevels([]string{"D", "E"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"A", "E"}, {"B", "C"}, {"D"}}, levels)
+}
+
+func TestDependencyGraph_StartLevels_UnknownTarget(t *testing.T) {
+	g := buildGraph(map[string]config.Process{
+		"A": {Command: "a"},
+	})
+
+	_, err := g.StartLevels([]string{"nonexistent"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown process")
+}
+
+func TestDependencyGraph_OptionalEdges(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"A": {Command: "a"},
+			"B": {Command: "b", DependsOn: []config.Dependency{{Name: "A", Optional: true}}},
+			"C": {Command: "c", DependsOn: []config.Dependency{{Name: "B"}}},
+		},
+	}
+	g := NewDependencyGraph(cfg)
+
+	assert.True(t, g.IsOptional("B", "A"))
+	assert.False(t, g.IsOptional("C", "B"))
+	assert.ElementsMatch(t, []string{"B", "C"}, g.Dependents("A"))
+	assert.Empty(t, g.RequiredDependents("A"))
+	assert.ElementsMatch(t, []string{"C"}, g.RequiredDependents("B"))
+	assert.ElementsMatch(t, []string{"B"}, g.RequiredDependencies("C"))
+
+	order, err := g.StartOrder([]string{"B"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A", "B"}, order)
+}
+
+func TestDependencyGraph_DOT(t *testing.T) {
+	g := buildGraph(map[string]config.Process{
+		"db":     {Command: "db"},
+		"api":    {Command: "api", DependsOn: []config.Dependency{{Name: "db"}, {Name: "cache", Optional: true}}},
+		"cache":  {Command: "cache"},
+		`odd"id`: {Command: "x"},
+	})
+	groups := map[string]config.Group{
+		"backend": {Processes: []string{"api", "db"}},
+		"infra":   {Processes: []string{"db", "cache"}},
+	}
+
+	want := `digraph shepherd {
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="backend";
+    "api";
+    "db";
+  }
+  subgraph cluster_1 {
+    label="infra";
+    "cache";
+  }
+  "odd\"id";
+  "api" -> "cache" [style=dashed];
+  "api" -> "db";
+}
+`
+	assert.Equal(t, want, g.DOT(groups))
+}
+
+func TestDependencyGraph_GroupOrdering(t *testing.T) {
+	g := NewDependencyGraph(&config.Config{
+		Groups: map[string]config.Group{
+			"infra":   {Processes: []string{"db", "queue"}},
+			"backend": {Processes: []string{"api", "worker"}, DependsOn: []string{"infra"}},
+		},
+		Processes: map[string]config.Process{
+			"db":     {Command: "db"},
+			"queue":  {Command: "queue"},
+			"api":    {Command: "api"},
+			"worker": {Command: "worker", DependsOn: []config.Dependency{{Name: "api"}}},
+		},
+	})
+	require.NoError(t, g.Validate())
+
+	order, err := g.StartOrder([]string{"api", "db", "queue", "worker"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "queue", "api", "worker"}, order)
+
+	// Group ordering doesn't pull in the other group's processes.
+	order, err = g.StartOrder([]string{"worker"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "worker"}, order)
+	assert.Empty(t, g.Dependents("db"))
+}
@@ -0,0 +1,90 @@
+package process
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// maxQueuedEvents is how many events a subscriber may fall behind by before
+// non-terminal events are dropped for it.
+const maxQueuedEvents = 1000
+
+// subscriber queues events for one listener, so a slow reader never blocks
+// the publisher. Once the queue is full only terminal events (failed and
+// stopped) are still queued; those are never dropped.
+type subscriber struct {
+	ch   chan StateEvent
+	wake chan struct{} // signals run that the queue is non-empty
+	done chan struct{} // closed when the subscription is cancelled
+
+	mu      sync.Mutex
+	queue   []StateEvent
+	dropped int // events dropped since the queue was last full
+}
+
+func newSubscriber() *subscriber {
+	s := &subscriber{
+		ch:   make(chan StateEvent, 100),
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// push queues ev for delivery.
+func (s *subscriber) push(ev StateEvent) {
+	s.mu.Lock()
+	if len(s.queue) >= maxQueuedEvents && !isTerminal(ev.NewState) {
+		s.dropped++
+		if s.dropped == 1 {
+			slog.Warn("event subscriber is falling behind, dropping non-terminal events", "process", ev.Name)
+		}
+		s.mu.Unlock()
+		return
+	}
+	s.queue = append(s.queue, ev)
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run moves queued events onto ch until the subscription is cancelled.
+func (s *subscriber) run() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			if s.dropped > 0 {
+				slog.Warn("event subscriber caught up", "dropped", s.dropped)
+				s.dropped = 0
+			}
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		ev := s.queue[0]
+		s.queue[0] = StateEvent{}
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- ev:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// isTerminal reports whether status ends a process's run. Events moving to
+// a terminal status are never dropped, so listeners always learn the
+// outcome.
+func isTerminal(status Status) bool {
+	return status == StatusFailed || status == StatusStopped || status == StatusCompleted
+}
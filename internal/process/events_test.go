@@ -0,0 +1,53 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriber_KeepsTerminalEventsWhenFull(t *testing.T) {
+	s := newSubscriber()
+	defer close(s.done)
+
+	// Nobody reads while far more events than the queue holds arrive.
+	total := maxQueuedEvents + cap(s.ch) + 500
+	for i := 0; i < total; i++ {
+		s.push(StateEvent{Name: "busy", NewState: StatusRunning})
+	}
+	s.push(StateEvent{Name: "busy", NewState: StatusFailed})
+	s.push(StateEvent{Name: "other", NewState: StatusStopped})
+
+	var got []StateEvent
+	for {
+		select {
+		case ev := <-s.ch:
+			got = append(got, ev)
+			continue
+		case <-time.After(200 * time.Millisecond):
+		}
+		break
+	}
+
+	assert.Less(t, len(got), total, "non-terminal events beyond the queue are dropped")
+	assert.Equal(t, StateEvent{Name: "busy", NewState: StatusFailed}, got[len(got)-2])
+	assert.Equal(t, StateEvent{Name: "other", NewState: StatusStopped}, got[len(got)-1])
+}
+
+func TestSubscriber_DeliversInOrder(t *testing.T) {
+	s := newSubscriber()
+	defer close(s.done)
+
+	for _, st := range []Status{StatusStarting, StatusRunning, StatusHealthy} {
+		s.push(StateEvent{Name: "a", NewState: st})
+	}
+	for _, want := range []Status{StatusStarting, StatusRunning, StatusHealthy} {
+		select {
+		case ev := <-s.ch:
+			assert.Equal(t, want, ev.NewState)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+	}
+}
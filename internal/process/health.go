@@ -0,0 +1,53 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+
+	"github.com/frontendtony/shepherd/internal/config"
+)
+
+// checkHealth runs a single probe of the given health check. It returns nil
+// if the probe passed. The probe is bounded by ctx.
+func checkHealth(ctx context.Context, hc config.HealthCheck, proc config.Process) error {
+	switch {
+	case hc.TCP != "":
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", hc.TCP)
+		if err != nil {
+			return fmt.Errorf("tcp %s: %w", hc.TCP, err)
+		}
+		conn.Close()
+		return nil
+
+	case hc.HTTP != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, hc.HTTP, nil)
+		if err != nil {
+			return fmt.Errorf("http %s: %w", hc.HTTP, err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("http %s: %w", hc.HTTP, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("http %s: unexpected status %d", hc.HTTP, resp.StatusCode)
+		}
+		return nil
+
+	case hc.Command != "":
+		cmd := exec.CommandContext(ctx, shellFor(proc), "-c", hc.Command)
+		if proc.WorkingDir != "" {
+			cmd.Dir = proc.WorkingDir
+		}
+		cmd.Env = buildEnv(proc)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("command %q: %w", hc.Command, err)
+		}
+		return nil
+	}
+	return nil
+}
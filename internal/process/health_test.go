@@ -0,0 +1,60 @@
+//go:build !ci
+
+package process
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHealth_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	hc := config.HealthCheck{TCP: addr}
+	assert.NoError(t, checkHealth(ctx, hc, config.Process{}))
+
+	ln.Close()
+	assert.Error(t, checkHealth(ctx, hc, config.Process{}))
+}
+
+func TestCheckHealth_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	hc := config.HealthCheck{HTTP: srv.URL}
+	assert.NoError(t, checkHealth(ctx, hc, config.Process{}))
+
+	err := checkHealth(ctx, config.HealthCheck{HTTP: srv.URL + "/down"}, config.Process{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
+
+func TestCheckHealth_Command(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, checkHealth(ctx, config.HealthCheck{Command: "true"}, config.Process{}))
+	assert.Error(t, checkHealth(ctx, config.HealthCheck{Command: "exit 1"}, config.Process{}))
+}
@@ -2,35 +2,72 @@ package process
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/frontendtony/shepherd/internal/config"
 	"github.com/frontendtony/shepherd/internal/logging"
 )
 
-const depHealthDelay = 2 * time.Second
-
 // StateEvent is emitted when a process changes state.
 type StateEvent struct {
-	Name     string
-	OldState Status
-	NewState Status
-	Error    string
+	Name     string `json:"name"`
+	OldState Status `json:"old_state"`
+	NewState Status `json:"new_state"`
+	Error    string `json:"error,omitempty"`
+
+	// Set on retry events: a transition to StatusRetrying means attempt
+	// Attempt is scheduled for NextRetryAt; StatusRetrying -> StatusStarting
+	// means that attempt is beginning now.
+	Attempt     int       `json:"attempt,omitempty"`
+	NextRetryAt time.Time `json:"next_retry_at"`
+}
+
+// MarshalJSON leaves out NextRetryAt when it isn't set.
+func (e StateEvent) MarshalJSON() ([]byte, error) {
+	type plain StateEvent
+	return json.Marshal(struct {
+		plain
+		NextRetryAt *time.Time `json:"next_retry_at,omitempty"`
+	}{plain(e), optionalTime(e.NextRetryAt)})
 }
 
+// defaultStopAllTimeout bounds how long StopAll waits for graceful shutdown
+// before killing whatever is left.
+const defaultStopAllTimeout = 15 * time.Second
+
 // ProcessManager orchestrates multiple processes with dependency resolution and retry logic.
 type ProcessManager struct {
-	config     *config.Config
-	graph      *DependencyGraph
+	config     *config.Config   // guarded by cfgMu; replaced by ApplyConfig
+	graph      *DependencyGraph // guarded by cfgMu
+	cfgMu      sync.RWMutex
 	processes  map[string]*ManagedProcess
 	logBuffers map[string]*logging.RingBuffer
-	events     chan StateEvent
+	watchers   map[string]context.CancelFunc // file watchers, guarded by mu
+	events     <-chan StateEvent             // created by the first call to Events
+	eventsOnce sync.Once
+	subs       map[*subscriber]struct{}
+	subsMu     sync.Mutex
 	mu         sync.RWMutex
 	ctx        context.Context
 	cancel     context.CancelFunc
+
+	stopAllTimeout time.Duration
+
+	// ptyCols and ptyRows are the size set by ResizePTYs, given to processes
+	// added later; zero means the PTY default.
+	ptyCols, ptyRows int
+
+	// startSlots limits concurrent starts to max_concurrent_starts; nil
+	// means no limit.
+	startSlots chan struct{}
 }
 
 // NewProcessManager creates a manager from the given config.
@@ -47,25 +84,84 @@ func NewProcessManager(ctx context.Context, cfg *config.Config) (*ProcessManager
 		graph:      graph,
 		processes:  make(map[string]*ManagedProcess),
 		logBuffers: make(map[string]*logging.RingBuffer),
-		events:     make(chan StateEvent, 100),
+		watchers:   make(map[string]context.CancelFunc),
+		subs:       make(map[*subscriber]struct{}),
 		ctx:        childCtx,
 		cancel:     cancel,
+
+		stopAllTimeout: defaultStopAllTimeout,
+	}
+
+	if cfg.StartLimit > 0 {
+		pm.startSlots = make(chan struct{}, cfg.StartLimit)
 	}
+	setTimestampFormat(cfg)
 
 	for name, proc := range cfg.Processes {
-		buf := logging.NewRingBuffer(logging.DefaultBufferSize)
+		buf := newLogBuffer(name, proc)
 		pm.logBuffers[name] = buf
 		pm.processes[name] = NewManagedProcess(name, proc, buf)
 	}
 
+	go pm.sampleUsage()
+
 	return pm, nil
 }
 
-// Events returns the channel for receiving state change events.
+// newLogBuffer creates the log buffer for a process from its log_buffer_size,
+// log_buffer_bytes, and log_max_line settings. Lines are tagged with the
+// process name. If the process has a log_file, the buffer starts out with
+// the end of it, so earlier output is visible before the process runs.
+func newLogBuffer(name string, proc config.Process) *logging.RingBuffer {
+	buf := logging.NewRingBuffer(proc.LogBufferSize)
+	buf.SetMaxBytes(proc.LogMaxBytes)
+	buf.SetMaxLineLength(proc.LogMaxLine)
+	buf.SetTag(name)
+	if proc.LogFile != "" {
+		if _, err := buf.LoadTail(proc.LogFile); err != nil {
+			buf.WriteString(fmt.Sprintf("[shepherd] Cannot read log file: %s", err))
+		}
+	}
+	return buf
+}
+
+// setTimestampFormat applies log_timestamp_format to every log buffer. The
+// config has already been validated, so a bad format can't reach here.
+func setTimestampFormat(cfg *config.Config) {
+	layout, _ := config.ParseTimestampFormat(cfg.LogTimestamp)
+	logging.SetTimestampFormat(layout)
+}
+
+// Events returns a shared subscription to state change events, created on
+// the first call. Every caller receives from the same channel, so each event
+// goes to only one of them; use Subscribe for independent listeners.
 func (pm *ProcessManager) Events() <-chan StateEvent {
+	pm.eventsOnce.Do(func() {
+		pm.events, _ = pm.Subscribe()
+	})
 	return pm.events
 }
 
+// Subscribe returns a new channel that receives every state event, and a
+// function that cancels the subscription. A subscriber that falls far behind
+// misses intermediate events, but never a move to failed or stopped.
+func (pm *ProcessManager) Subscribe() (<-chan StateEvent, func()) {
+	sub := newSubscriber()
+	pm.subsMu.Lock()
+	pm.subs[sub] = struct{}{}
+	pm.subsMu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			pm.subsMu.Lock()
+			delete(pm.subs, sub)
+			pm.subsMu.Unlock()
+			close(sub.done)
+		})
+	}
+}
+
 // GetAllStates returns a snapshot of all process states.
 func (pm *ProcessManager) GetAllStates() []ProcessState {
 	pm.mu.RLock()
@@ -78,6 +174,35 @@ func (pm *ProcessManager) GetAllStates() []ProcessState {
 	return states
 }
 
+// GetState returns the state of the named process, and false if there is no
+// such process.
+func (pm *ProcessManager) GetState(name string) (ProcessState, bool) {
+	pm.mu.RLock()
+	p, ok := pm.processes[name]
+	pm.mu.RUnlock()
+	if !ok {
+		return ProcessState{}, false
+	}
+	return p.State(), true
+}
+
+// RunningNames returns the sorted names of processes that are running or on
+// their way up (starting, waiting to retry, or waiting for a scheduled run).
+func (pm *ProcessManager) RunningNames() []string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	var names []string
+	for name, p := range pm.processes {
+		status := p.State().Status
+		if status.IsRunning() || status == StatusStarting || status == StatusRetrying || status == StatusScheduled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetLogBuffer returns the log buffer for a specific process.
 func (pm *ProcessManager) GetLogBuffer(name string) *logging.RingBuffer {
 	pm.mu.RLock()
@@ -85,33 +210,134 @@ func (pm *ProcessManager) GetLogBuffer(name string) *logging.RingBuffer {
 	return pm.logBuffers[name]
 }
 
+// TailLogs subscribes to the named process's log lines as they are written;
+// see logging.RingBuffer.Subscribe.
+func (pm *ProcessManager) TailLogs(name string) (<-chan string, func(), error) {
+	buf := pm.GetLogBuffer(name)
+	if buf == nil {
+		return nil, nil, fmt.Errorf("unknown process: %s", name)
+	}
+	lines, unsubscribe := buf.Subscribe()
+	return lines, unsubscribe, nil
+}
+
 // GetConfig returns the config.
 func (pm *ProcessManager) GetConfig() *config.Config {
+	return pm.currentConfig()
+}
+
+// ApplyConfig replaces the manager's config with a reloaded one. Running
+// processes are not restarted: a changed command, env, or other start setting
+// takes effect the next time each process is started, so callers restart the
+// processes that config.DiffConfigs reports as changed. New processes are
+// added stopped, and processes no longer in the config are stopped and
+// removed. Log buffers are kept, so a log_buffer_size change only applies to
+// new processes.
+func (pm *ProcessManager) ApplyConfig(cfg *config.Config) error {
+	graph := NewDependencyGraph(cfg)
+	if err := graph.Validate(); err != nil {
+		return fmt.Errorf("invalid dependency graph: %w", err)
+	}
+
+	pm.mu.Lock()
+	var removed []string
+	for name := range pm.processes {
+		if _, ok := cfg.Processes[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	for name, proc := range cfg.Processes {
+		if p, ok := pm.processes[name]; ok {
+			p.SetConfig(proc)
+			pm.logBuffers[name].SetMaxBytes(proc.LogMaxBytes)
+			pm.logBuffers[name].SetMaxLineLength(proc.LogMaxLine)
+			continue
+		}
+		buf := newLogBuffer(name, proc)
+		pm.logBuffers[name] = buf
+		pm.processes[name] = NewManagedProcess(name, proc, buf)
+		if pm.ptyCols > 0 {
+			pm.processes[name].SetSize(pm.ptyCols, pm.ptyRows)
+		}
+	}
+	pm.mu.Unlock()
+
+	pm.cfgMu.Lock()
+	pm.config = cfg
+	pm.graph = graph
+	pm.cfgMu.Unlock()
+	setTimestampFormat(cfg)
+
+	for _, name := range removed {
+		pm.mu.RLock()
+		p, ok := pm.processes[name]
+		pm.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		status := p.State().Status
+		if status.IsRunning() || status == StatusStarting || status == StatusRetrying ||
+			status == StatusScheduled {
+			if err := pm.stopSingle(name); err != nil {
+				slog.Warn("failed to stop removed process", "process", name, "error", err)
+			}
+		}
+
+		pm.stopWatching(name)
+		pm.mu.Lock()
+		delete(pm.processes, name)
+		delete(pm.logBuffers, name)
+		pm.mu.Unlock()
+	}
+	return nil
+}
+
+func (pm *ProcessManager) currentConfig() *config.Config {
+	pm.cfgMu.RLock()
+	defer pm.cfgMu.RUnlock()
 	return pm.config
 }
 
+func (pm *ProcessManager) currentGraph() *DependencyGraph {
+	pm.cfgMu.RLock()
+	defer pm.cfgMu.RUnlock()
+	return pm.graph
+}
+
 // StartProcess starts a process and all its transitive dependencies.
 func (pm *ProcessManager) StartProcess(name string) error {
-	order, err := pm.graph.StartOrder([]string{name})
+	res, err := pm.startTargets([]string{name})
 	if err != nil {
 		return err
 	}
-	return pm.startInOrder(order)
+	return res.Err()
 }
 
 // StopProcess stops a process and all its dependents first.
 func (pm *ProcessManager) StopProcess(name string) error {
+	pm.mu.RLock()
+	_, ok := pm.processes[name]
+	pm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown process: %s", name)
+	}
+
 	// Find dependents that are currently running.
-	dependents := pm.graph.Dependents(name)
+	dependents := pm.currentGraph().Dependents(name)
 
 	// Stop dependents first (they depend on this process).
 	for _, dep := range dependents {
 		pm.mu.RLock()
-		p := pm.processes[dep]
+		p, ok := pm.processes[dep]
 		pm.mu.RUnlock()
+		if !ok {
+			continue
+		}
 
 		state := p.State()
-		if state.Status == StatusRunning || state.Status == StatusStarting || state.Status == StatusRetrying {
+		if state.Status.IsRunning() || state.Status == StatusStarting || state.Status == StatusRetrying ||
+			state.Status == StatusScheduled {
 			if err := pm.stopSingle(dep); err != nil {
 				slog.Warn("failed to stop dependent", "process", dep, "error", err)
 			}
@@ -121,21 +347,101 @@ func (pm *ProcessManager) StopProcess(name string) error {
 	return pm.stopSingle(name)
 }
 
+// StopProcessOnly stops a single process without stopping its dependents.
+// Dependents keep running and may fail on their own once it is gone.
+func (pm *ProcessManager) StopProcessOnly(name string) error {
+	pm.mu.RLock()
+	_, ok := pm.processes[name]
+	pm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown process: %s", name)
+	}
+	return pm.stopSingle(name)
+}
+
+// KillProcess sends SIGKILL to a process group immediately instead of waiting
+// for the stop signal's timeout. Dependents are left running. A pending retry
+// is cancelled.
+func (pm *ProcessManager) KillProcess(name string) error {
+	pm.mu.RLock()
+	p, ok := pm.processes[name]
+	pm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown process: %s", name)
+	}
+
+	oldStatus := p.State().Status
+	switch {
+	case oldStatus == StatusRetrying || oldStatus == StatusStarting:
+		return pm.stopSingle(name)
+	case !oldStatus.IsRunning() && oldStatus != StatusStarting && oldStatus != StatusStopping:
+		return nil
+	}
+
+	p.Kill()
+	<-p.Wait()
+	pm.emitEvent(name, oldStatus, StatusStopped, "")
+	return nil
+}
+
+// ResizePTYs sets the terminal size seen by processes that run on a PTY, so
+// output that adapts to the terminal fits where it is shown. Running
+// processes are resized at once; the rest start at this size.
+func (pm *ProcessManager) ResizePTYs(cols, rows int) {
+	if cols <= 0 || rows <= 0 {
+		return
+	}
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.ptyCols, pm.ptyRows = cols, rows
+	for _, p := range pm.processes {
+		p.SetSize(cols, rows)
+	}
+}
+
+// SignalProcess sends sig to a running process's group, for daemons that
+// reopen logs or dump state on signals such as SIGUSR1. The process keeps
+// its status; if the signal makes it exit, that is handled as usual.
+func (pm *ProcessManager) SignalProcess(name string, sig syscall.Signal) error {
+	pm.mu.RLock()
+	p, ok := pm.processes[name]
+	pm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown process: %s", name)
+	}
+	if err := p.Signal(sig); err != nil {
+		return err
+	}
+	p.log.WriteString(fmt.Sprintf("[shepherd] Sent %s", config.SignalName(sig)))
+	return nil
+}
+
 // RestartProcess stops a process and its dependents, then restarts the process.
 // Dependents that were failed due to this dependency are auto-restarted.
 func (pm *ProcessManager) RestartProcess(name string) error {
+	pm.mu.RLock()
+	_, ok := pm.processes[name]
+	pm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown process: %s", name)
+	}
+
 	// Track which dependents were running or failed due to dependency.
-	dependents := pm.graph.Dependents(name)
+	dependents := pm.currentGraph().Dependents(name)
 	restartDeps := make([]string, 0)
 
 	for _, dep := range dependents {
 		pm.mu.RLock()
-		p := pm.processes[dep]
+		p, ok := pm.processes[dep]
 		pm.mu.RUnlock()
+		if !ok {
+			continue
+		}
 
 		state := p.State()
-		if state.Status == StatusRunning || state.Status == StatusStarting ||
-			state.Status == StatusFailed || state.Status == StatusRetrying {
+		if state.Status.IsRunning() || state.Status == StatusStarting ||
+			state.Status == StatusFailed || state.Status == StatusRetrying ||
+			state.Status == StatusScheduled {
 			restartDeps = append(restartDeps, dep)
 		}
 	}
@@ -145,6 +451,13 @@ func (pm *ProcessManager) RestartProcess(name string) error {
 	}
 
 	// Start the process itself.
+	pm.mu.RLock()
+	p, ok := pm.processes[name]
+	pm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown process: %s", name)
+	}
+	p.IncrementRestarts()
 	if err := pm.startSingle(name); err != nil {
 		return fmt.Errorf("restarting %s: %w", name, err)
 	}
@@ -152,8 +465,11 @@ func (pm *ProcessManager) RestartProcess(name string) error {
 	// Auto-restart dependents.
 	for _, dep := range restartDeps {
 		pm.mu.RLock()
-		p := pm.processes[dep]
+		p, ok := pm.processes[dep]
 		pm.mu.RUnlock()
+		if !ok {
+			continue
+		}
 		p.ResetRetryCount()
 
 		if err := pm.startSingle(dep); err != nil {
@@ -164,66 +480,277 @@ func (pm *ProcessManager) RestartProcess(name string) error {
 	return nil
 }
 
-// StartGroup starts all processes in the named group.
-func (pm *ProcessManager) StartGroup(groupName string) error {
-	group, ok := pm.config.Groups[groupName]
+// StartGroup starts all processes in the named group, after the processes of
+// any groups it depends on.
+func (pm *ProcessManager) StartGroup(groupName string) (*StartResult, error) {
+	if _, ok := pm.currentConfig().Groups[groupName]; !ok {
+		return nil, fmt.Errorf("unknown group: %s", groupName)
+	}
+	return pm.startTargets(groupTargets(pm.currentConfig(), groupName))
+}
+
+// StartProcesses starts the named processes and their dependencies, each
+// once, in dependency order.
+func (pm *ProcessManager) StartProcesses(names []string) (*StartResult, error) {
+	return pm.startTargets(names)
+}
+
+// StartStack starts all groups in the named stack, and any groups they
+// depend on.
+func (pm *ProcessManager) StartStack(stackName string) (*StartResult, error) {
+	if _, ok := pm.currentConfig().Stacks[stackName]; !ok {
+		return nil, fmt.Errorf("unknown stack: %s", stackName)
+	}
+
+	allTargets, err := stackTargets(pm.currentConfig(), stackName)
+	if err != nil {
+		return nil, err
+	}
+	return pm.startTargets(allTargets)
+}
+
+// startTargets starts targets and their dependencies. The error covers
+// problems that stop anything from starting, such as a dependency cycle;
+// per-process failures are reported in the result.
+func (pm *ProcessManager) startTargets(targets []string) (*StartResult, error) {
+	levels, err := pm.currentGraph().StartLevels(targets)
+	if err != nil {
+		return nil, err
+	}
+	return pm.startInLevels(levels)
+}
+
+// stackTargets returns the processes of every group in the named stack and
+// of the groups they depend on.
+func stackTargets(cfg *config.Config, stackName string) ([]string, error) {
+	var targets []string
+	for _, groupName := range cfg.Stacks[stackName].Groups {
+		if _, ok := cfg.Groups[groupName]; !ok {
+			return nil, fmt.Errorf("stack %s references unknown group %s", stackName, groupName)
+		}
+		targets = append(targets, groupTargets(cfg, groupName)...)
+	}
+	return targets, nil
+}
+
+// groupTargets returns the processes of the named group and of every group it
+// depends on, directly or transitively.
+func groupTargets(cfg *config.Config, groupName string) []string {
+	groups := cfg.Groups
+	visited := make(map[string]bool)
+	var targets []string
+
+	var walk func(name string)
+	walk = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		group := groups[name]
+		targets = append(targets, group.Processes...)
+		for _, dep := range group.DependsOn {
+			walk(dep)
+		}
+	}
+
+	walk(groupName)
+	return targets
+}
+
+// RestartGroup restarts all processes in the named group.
+func (pm *ProcessManager) RestartGroup(groupName string) error {
+	group, ok := pm.currentConfig().Groups[groupName]
 	if !ok {
 		return fmt.Errorf("unknown group: %s", groupName)
 	}
+	return pm.RestartProcesses(group.Processes)
+}
+
+// StopGroup stops all processes in the named group, along with any processes
+// that depend on them, in reverse dependency order.
+func (pm *ProcessManager) StopGroup(groupName string) error {
+	group, ok := pm.currentConfig().Groups[groupName]
+	if !ok {
+		return fmt.Errorf("unknown group: %s", groupName)
+	}
+	return pm.StopProcesses(group.Processes)
+}
+
+// StopProcesses stops the named processes and their active dependents. Each
+// process is stopped once, dependents before their dependencies, rather than
+// cascading from every name in turn as repeated StopProcess calls would.
+func (pm *ProcessManager) StopProcesses(names []string) error {
+	stop := make(map[string]bool)
+	for _, name := range names {
+		pm.mu.RLock()
+		_, ok := pm.processes[name]
+		pm.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("unknown process: %s", name)
+		}
+		stop[name] = true
+	}
+	for _, name := range names {
+		for _, dep := range pm.currentGraph().Dependents(name) {
+			pm.mu.RLock()
+			p, ok := pm.processes[dep]
+			pm.mu.RUnlock()
+			if !ok {
+				continue
+			}
 
-	// Collect all processes and their dependencies.
-	var allTargets []string
-	allTargets = append(allTargets, group.Processes...)
+			state := p.State()
+			if state.Status.IsRunning() || state.Status == StatusStarting || state.Status == StatusRetrying ||
+				state.Status == StatusScheduled {
+				stop[dep] = true
+			}
+		}
+	}
 
-	order, err := pm.graph.StartOrder(allTargets)
+	targets := make([]string, 0, len(stop))
+	for name := range stop {
+		targets = append(targets, name)
+	}
+	order, err := pm.currentGraph().StopOrder(targets)
 	if err != nil {
 		return err
 	}
-	return pm.startInOrder(order)
+
+	// StopOrder also lists the targets' dependencies, which stay up.
+	var firstErr error
+	for _, name := range order {
+		if !stop[name] {
+			continue
+		}
+		if err := pm.stopSingle(name); err != nil {
+			slog.Warn("failed to stop process", "process", name, "error", err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("stopping %s: %w", name, err)
+			}
+		}
+	}
+	return firstErr
 }
 
-// StartStack starts all groups in the named stack.
-func (pm *ProcessManager) StartStack(stackName string) error {
-	stack, ok := pm.config.Stacks[stackName]
-	if !ok {
+// RestartStack restarts all processes in the groups of the named stack, and
+// of the groups they depend on, mirroring StartStack.
+func (pm *ProcessManager) RestartStack(stackName string) error {
+	if _, ok := pm.currentConfig().Stacks[stackName]; !ok {
 		return fmt.Errorf("unknown stack: %s", stackName)
 	}
 
-	var allTargets []string
-	for _, groupName := range stack.Groups {
-		group, ok := pm.config.Groups[groupName]
+	allTargets, err := stackTargets(pm.currentConfig(), stackName)
+	if err != nil {
+		return err
+	}
+	return pm.RestartProcesses(allTargets)
+}
+
+// RestartProcesses stops the named processes in reverse dependency order, then
+// starts them again in dependency order. Dependents outside the set that were
+// active are stopped first and brought back afterwards, as RestartProcess does.
+func (pm *ProcessManager) RestartProcesses(names []string) error {
+	restart := make(map[string]bool)
+	for _, name := range names {
+		pm.mu.RLock()
+		_, ok := pm.processes[name]
+		pm.mu.RUnlock()
 		if !ok {
-			return fmt.Errorf("stack %s references unknown group %s", stackName, groupName)
+			return fmt.Errorf("unknown process: %s", name)
+		}
+		restart[name] = true
+	}
+	for _, name := range names {
+		for _, dep := range pm.currentGraph().Dependents(name) {
+			pm.mu.RLock()
+			p, ok := pm.processes[dep]
+			pm.mu.RUnlock()
+			if !ok {
+				continue
+			}
+
+			state := p.State()
+			if state.Status.IsRunning() || state.Status == StatusStarting ||
+				state.Status == StatusFailed || state.Status == StatusRetrying ||
+				state.Status == StatusScheduled {
+				restart[dep] = true
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(restart))
+	for name := range restart {
+		targets = append(targets, name)
+	}
+	levels, err := pm.currentGraph().StartLevels(targets)
+	if err != nil {
+		return err
+	}
+
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, name := range levels[i] {
+			if !restart[name] {
+				continue
+			}
+			pm.mu.RLock()
+			p, ok := pm.processes[name]
+			pm.mu.RUnlock()
+			if !ok {
+				continue
+			}
+
+			switch status := p.State().Status; {
+			case status.IsRunning():
+				p.IncrementRestarts()
+				fallthrough
+			case status == StatusStarting || status == StatusRetrying || status == StatusScheduled:
+				if err := pm.stopSingle(name); err != nil {
+					return fmt.Errorf("stopping %s for restart: %w", name, err)
+				}
+			}
+			p.ResetRetryCount()
 		}
-		allTargets = append(allTargets, group.Processes...)
 	}
 
-	order, err := pm.graph.StartOrder(allTargets)
+	res, err := pm.startInLevels(levels)
 	if err != nil {
 		return err
 	}
-	return pm.startInOrder(order)
+	return res.Err()
 }
 
-// Resolve resolves a name to its type (stack, group, or process).
+// Resolve resolves a name to its type (stack, group, process, or tag). Tags
+// are written with config.TagPrefix, e.g. "tag:backend".
 func (pm *ProcessManager) Resolve(name string) (kind string, err error) {
-	if _, ok := pm.config.Stacks[name]; ok {
+	return resolve(pm.currentConfig(), name)
+}
+
+// resolve is Resolve against cfg.
+func resolve(cfg *config.Config, name string) (kind string, err error) {
+	if tag, ok := strings.CutPrefix(name, config.TagPrefix); ok {
+		if len(cfg.TaggedProcesses(tag)) == 0 {
+			return "", fmt.Errorf("unknown tag: %s (no process carries it)", tag)
+		}
+		return "tag", nil
+	}
+	if _, ok := cfg.Stacks[name]; ok {
 		return "stack", nil
 	}
-	if _, ok := pm.config.Groups[name]; ok {
+	if _, ok := cfg.Groups[name]; ok {
 		return "group", nil
 	}
-	if _, ok := pm.config.Processes[name]; ok {
+	if _, ok := cfg.Processes[name]; ok {
 		return "process", nil
 	}
 	return "", fmt.Errorf("unknown name: %s (not a stack, group, or process)", name)
 }
 
-// StartByName resolves a name and starts the corresponding stack/group/process.
-func (pm *ProcessManager) StartByName(name string) error {
+// StartByName resolves a name and starts the corresponding stack, group,
+// process, or tag.
+func (pm *ProcessManager) StartByName(name string) (*StartResult, error) {
 	kind, err := pm.Resolve(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	switch kind {
 	case "stack":
@@ -231,19 +758,70 @@ func (pm *ProcessManager) StartByName(name string) error {
 	case "group":
 		return pm.StartGroup(name)
 	case "process":
-		return pm.StartProcess(name)
+		return pm.startTargets([]string{name})
+	case "tag":
+		return pm.StartTag(strings.TrimPrefix(name, config.TagPrefix))
 	}
-	return nil
+	return &StartResult{}, nil
+}
+
+// StartPlan returns the processes StartByName would start for name under
+// cfg, in the order it would start them. It needs only the config, so a plan
+// can be shown without creating a manager. Processes that are already
+// running are included; StartByName skips them.
+func StartPlan(cfg *config.Config, name string) ([]string, error) {
+	kind, err := resolve(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	var targets []string
+	switch kind {
+	case "stack":
+		if targets, err = stackTargets(cfg, name); err != nil {
+			return nil, err
+		}
+	case "group":
+		targets = groupTargets(cfg, name)
+	case "process":
+		targets = []string{name}
+	case "tag":
+		targets = cfg.TaggedProcesses(strings.TrimPrefix(name, config.TagPrefix))
+	}
+	return NewDependencyGraph(cfg).StartOrder(targets)
 }
 
-// StopAll stops all running processes in reverse dependency order.
+// StartTag starts every process carrying tag, plus their dependencies.
+func (pm *ProcessManager) StartTag(tag string) (*StartResult, error) {
+	targets := pm.currentConfig().TaggedProcesses(tag)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("unknown tag: %s", tag)
+	}
+	return pm.startTargets(targets)
+}
+
+// StartAll starts every configured process in dependency order, computing a
+// single plan so shared dependencies are started once.
+func (pm *ProcessManager) StartAll() (*StartResult, error) {
+	cfg := pm.currentConfig()
+	targets := make([]string, 0, len(cfg.Processes))
+	for name := range cfg.Processes {
+		targets = append(targets, name)
+	}
+	sort.Strings(targets)
+	return pm.startTargets(targets)
+}
+
+// StopAll stops all running processes in reverse dependency order. Processes
+// in the same dependency level are stopped in parallel. If everything hasn't
+// stopped within stopAllTimeout, the remaining processes are killed.
 func (pm *ProcessManager) StopAll() error {
 	pm.mu.RLock()
 	var running []string
 	for name, p := range pm.processes {
 		state := p.State()
-		if state.Status == StatusRunning || state.Status == StatusStarting ||
-			state.Status == StatusRetrying || state.Status == StatusStopping {
+		if state.Status.IsRunning() || state.Status == StatusStarting ||
+			state.Status == StatusRetrying || state.Status == StatusStopping ||
+			state.Status == StatusScheduled {
 			running = append(running, name)
 		}
 	}
@@ -253,29 +831,61 @@ func (pm *ProcessManager) StopAll() error {
 		return nil
 	}
 
-	order, err := pm.graph.StopOrder(running)
+	levels, err := pm.currentGraph().StartLevels(running)
 	if err != nil {
-		// If graph fails, just stop everything.
-		for _, name := range running {
-			pm.stopSingle(name)
+		// If graph fails, just stop everything at once.
+		levels = [][]string{running}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := len(levels) - 1; i >= 0; i-- {
+			var wg sync.WaitGroup
+			for _, name := range levels[i] {
+				pm.mu.RLock()
+				p, ok := pm.processes[name]
+				pm.mu.RUnlock()
+				if !ok {
+					continue
+				}
+
+				state := p.State()
+				if !state.Status.IsRunning() && state.Status != StatusStarting &&
+					state.Status != StatusRetrying && state.Status != StatusScheduled {
+					continue
+				}
+				wg.Add(1)
+				go func(name string) {
+					defer wg.Done()
+					if err := pm.stopSingle(name); err != nil {
+						slog.Warn("failed to stop process during StopAll", "process", name, "error", err)
+					}
+				}(name)
+			}
+			wg.Wait()
 		}
+	}()
+
+	timer := time.NewTimer(pm.stopAllTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
 		return nil
+	case <-timer.C:
 	}
 
-	for _, name := range order {
+	slog.Warn("StopAll timed out, killing remaining processes", "timeout", pm.stopAllTimeout)
+	for _, name := range running {
 		pm.mu.RLock()
-		p := pm.processes[name]
+		p, ok := pm.processes[name]
 		pm.mu.RUnlock()
-
-		state := p.State()
-		if state.Status == StatusRunning || state.Status == StatusStarting ||
-			state.Status == StatusRetrying {
-			if err := pm.stopSingle(name); err != nil {
-				slog.Warn("failed to stop process during StopAll", "process", name, "error", err)
-			}
+		if !ok {
+			continue
 		}
+		p.Kill()
 	}
-
+	<-done
 	return nil
 }
 
@@ -285,92 +895,237 @@ func (pm *ProcessManager) Shutdown() {
 	pm.StopAll()
 }
 
-// startInOrder starts processes sequentially in dependency order, skipping already-running ones.
-func (pm *ProcessManager) startInOrder(order []string) error {
-	for _, name := range order {
-		select {
-		case <-pm.ctx.Done():
-			return pm.ctx.Err()
-		default:
+// startInLevels starts processes level by level. All processes in a level are
+// launched in parallel; each waits for its direct dependencies (which live in
+// earlier levels) to become healthy first. Already-running processes are
+// skipped. Every level is attempted; the result says how each process fared.
+// The error is only set if shepherd shut down part way; see abortStart.
+func (pm *ProcessManager) startInLevels(levels [][]string) (*StartResult, error) {
+	res := &StartResult{}
+	for li, level := range levels {
+		if pm.ctx.Err() != nil {
+			return pm.abortStart(res, levels[li:])
 		}
 
-		pm.mu.RLock()
-		p := pm.processes[name]
-		pm.mu.RUnlock()
+		started := make([]bool, len(level))
+		errs := make([]error, len(level))
+		var wg sync.WaitGroup
+		for i, name := range level {
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				started[i], errs[i] = pm.startWhenReady(name)
+			}(i, name)
+		}
+		wg.Wait()
+
+		// Keep going after a failure: processes that only depend on the
+		// failed one optionally can still start, and required dependents
+		// fail themselves in startWhenReady.
+		for i, name := range level {
+			switch {
+			case errs[i] != nil:
+				res.Failed = append(res.Failed, StartFailure{Name: name, Err: errs[i]})
+			case started[i]:
+				res.Started = append(res.Started, name)
+			default:
+				res.Skipped = append(res.Skipped, name)
+			}
+		}
+	}
+	if pm.ctx.Err() != nil {
+		return pm.abortStart(res, nil)
+	}
+	return res, nil
+}
 
-		state := p.State()
+// abortStart undoes a start interrupted by shutdown, so it doesn't leave
+// behind processes whose dependents never came up. The processes it started
+// are stopped again, newest first, and reported as rolled back; those that
+// gave up waiting because of the shutdown, and the levels in rest, are
+// reported as skipped.
+func (pm *ProcessManager) abortStart(res *StartResult, rest [][]string) (*StartResult, error) {
+	for i := len(res.Started) - 1; i >= 0; i-- {
+		if err := pm.stopSingle(res.Started[i]); err != nil {
+			slog.Warn("failed to roll back interrupted start", "process", res.Started[i], "error", err)
+		}
+	}
+	res.RolledBack = res.Started
+	res.Started = nil
 
-		// Skip already running.
-		if state.Status == StatusRunning {
+	failed := res.Failed[:0]
+	for _, f := range res.Failed {
+		if errors.Is(f.Err, pm.ctx.Err()) {
+			res.Skipped = append(res.Skipped, f.Name)
 			continue
 		}
+		failed = append(failed, f)
+	}
+	res.Failed = failed
 
-		// Check if any dependency has permanently failed.
-		deps := pm.graph.Dependencies(name)
-		for _, dep := range deps {
-			pm.mu.RLock()
-			dp := pm.processes[dep]
-			pm.mu.RUnlock()
+	for _, level := range rest {
+		res.Skipped = append(res.Skipped, level...)
+	}
+	return res, pm.ctx.Err()
+}
 
-			depState := dp.State()
-			if depState.Status == StatusFailed {
-				errMsg := fmt.Sprintf("dependency %s failed", dep)
-				p.SetStatus(StatusFailed)
-				p.SetError(errMsg)
-				pm.emitEvent(name, state.Status, StatusFailed, errMsg)
-				return fmt.Errorf("cannot start %s: %s", name, errMsg)
-			}
+// startWhenReady starts a process once its dependencies are healthy, and
+// reports false if it was already running. It fails the process immediately
+// if any required dependency has permanently failed. Optional dependencies
+// are waited for, but their failure is ignored.
+func (pm *ProcessManager) startWhenReady(name string) (bool, error) {
+	pm.mu.RLock()
+	p, ok := pm.processes[name]
+	pm.mu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("unknown process: %s", name)
+	}
+
+	state := p.State()
+
+	// Skip already running.
+	if state.Status.IsRunning() {
+		return false, nil
+	}
+
+	// Check if any required dependency has permanently failed.
+	deps := pm.currentGraph().RequiredDependencies(name)
+	for _, dep := range deps {
+		pm.mu.RLock()
+		dp, ok := pm.processes[dep]
+		pm.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		depState := dp.State()
+		if depState.Status == StatusFailed {
+			errMsg := fmt.Sprintf("dependency %s failed", dep)
+			p.SetStatus(StatusFailed)
+			p.SetError(errMsg)
+			pm.emitEvent(name, state.Status, StatusFailed, errMsg)
+			return false, fmt.Errorf("cannot start %s: %s", name, errMsg)
 		}
+	}
 
-		// Wait for direct dependencies to be running and healthy.
-		procCfg := pm.config.Processes[name]
-		for _, dep := range procCfg.DependsOn {
-			if err := pm.waitForHealthy(dep); err != nil {
-				return fmt.Errorf("waiting for dependency %s: %w", dep, err)
+	// Wait for direct dependencies to be running and healthy.
+	procCfg := pm.currentConfig().Processes[name]
+	for _, dep := range procCfg.DependsOn {
+		if err := pm.waitForHealthy(dep.Name, dep.Condition); err != nil {
+			if dep.Optional {
+				continue
 			}
+			return false, fmt.Errorf("waiting for dependency %s: %w", dep.Name, err)
 		}
+	}
 
-		if err := pm.startSingle(name); err != nil {
-			return err
+	// Processes in groups this one's group depends on only matter if they
+	// are being started too; a failure doesn't block this process.
+	for _, other := range pm.currentGraph().After(name) {
+		pm.mu.RLock()
+		op, ok := pm.processes[other]
+		pm.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		switch status := op.State().Status; {
+		case status.IsRunning(), status == StatusStarting, status == StatusRetrying:
+			if err := pm.waitForHealthy(other, ""); err != nil {
+				slog.Debug("not waiting for group dependency", "process", name, "after", other, "error", err)
+			}
 		}
 	}
-	return nil
+
+	if err := pm.startSingle(name); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // startSingle starts a single process and sets up monitoring.
 func (pm *ProcessManager) startSingle(name string) error {
 	pm.mu.RLock()
-	p := pm.processes[name]
+	p, ok := pm.processes[name]
 	pm.mu.RUnlock()
+	if !ok {
+		// Removed by ApplyConfig while a start was pending.
+		return fmt.Errorf("unknown process: %s", name)
+	}
+	if pm.currentConfig().Processes[name].Schedule != "" {
+		return pm.armSchedule(name, p, p.State().Status)
+	}
+	return pm.launch(name, p, p.StartRun)
+}
 
+// launch calls start once a start slot is free, then sets up monitoring.
+// start reports false if the start was cancelled while it waited. Every run
+// after the first is preceded by a separator line in the log.
+func (pm *ProcessManager) launch(name string, p *ManagedProcess, start func() (Run, error)) error {
 	oldStatus := p.State().Status
-	if err := p.Start(); err != nil {
+	release, err := pm.acquireStartSlot()
+	if err != nil {
+		return err
+	}
+
+	// Mark where this run's output begins so it doesn't blur into the
+	// previous run's. Lines preloaded from the log file don't count as a
+	// previous run.
+	if p.HasRun() {
+		p.log.WriteString(restartSeparator(time.Now()))
+	}
+
+	run, err := start()
+	if err != nil {
+		release()
 		pm.emitEvent(name, oldStatus, StatusFailed, err.Error())
 		return err
 	}
+	if run.ID == 0 {
+		release()
+		return nil
+	}
 	pm.emitEvent(name, oldStatus, StatusRunning, "")
+	go pm.releaseWhenReady(p, pm.currentConfig().Processes[name], release)
+	if pm.currentConfig().Processes[name].StartTimeout != nil {
+		go pm.enforceStartTimeout(name, p)
+	}
 
-	// Monitor this process for exit.
-	go pm.monitor(name)
+	// Monitor this run for exit.
+	go pm.monitor(name, p, run)
+
+	if pm.currentConfig().Processes[name].HealthCheck.Configured() {
+		go pm.watchHealth(name, p)
+	}
+	if pm.currentConfig().Processes[name].Watch.Configured() {
+		pm.startWatching(name, p)
+	}
 
 	return nil
 }
 
+// restartSeparator is the log line written between runs of a process.
+func restartSeparator(t time.Time) string {
+	return fmt.Sprintf("──── restarted at %s ────", t.Format(logging.TimestampFormat()))
+}
+
 // stopSingle stops a single process, cancelling any pending retry.
 func (pm *ProcessManager) stopSingle(name string) error {
 	pm.mu.RLock()
-	p := pm.processes[name]
+	p, ok := pm.processes[name]
 	pm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown process: %s", name)
+	}
+	pm.stopWatching(name)
 
-	oldStatus := p.State().Status
-
-	// Cancel any pending retry by setting to stopping.
-	if oldStatus == StatusRetrying {
-		p.SetStatus(StatusStopped)
+	// A pending retry is cancelled rather than signalled: there is no
+	// process to stop yet.
+	if oldStatus, cancelled := p.CancelRetry(); cancelled {
 		pm.emitEvent(name, oldStatus, StatusStopped, "")
 		return nil
 	}
 
+	oldStatus := p.State().Status
 	if err := p.Stop(); err != nil {
 		return err
 	}
@@ -378,51 +1133,61 @@ func (pm *ProcessManager) stopSingle(name string) error {
 	return nil
 }
 
-// monitor watches a process and handles retries on failure.
-func (pm *ProcessManager) monitor(name string) {
-	pm.mu.RLock()
-	p := pm.processes[name]
-	pm.mu.RUnlock()
-
-	<-p.Wait()
-
-	state := p.State()
+// monitor waits for run to exit and handles restarts according to the
+// process's restart policy and retry config. p is passed in rather than
+// looked up because ApplyConfig may remove the process from the manager while
+// it runs. If the process has been started again by the time run exits, as
+// a restart does, the newer run's monitor takes over.
+func (pm *ProcessManager) monitor(name string, p *ManagedProcess, run Run) {
+	<-run.Done
 
-	// If process was intentionally stopped, nothing to do.
-	if state.Status == StatusStopped {
+	state, current := p.RunState(run)
+	if !current {
 		return
 	}
+	procCfg := pm.currentConfig().Processes[name]
 
-	// Process failed - check retry logic.
-	procCfg := pm.config.Processes[name]
-	retryCount := state.RetryCount
-
-	if shouldRetry(retryCount, procCfg.Retry) {
-		backoff := nextBackoff(retryCount, procCfg.Retry)
-		nextRetry := time.Now().Add(backoff)
-		p.SetStatus(StatusRetrying)
-		p.SetRetryState(retryCount+1, nextRetry)
-		pm.emitEvent(name, StatusFailed, StatusRetrying, "")
-
-		slog.Info("scheduling retry", "process", name, "attempt", retryCount+1, "backoff", backoff)
-
-		// Wait for backoff period.
-		select {
-		case <-pm.ctx.Done():
+	// A scheduled process waits for its next run whatever the outcome; the
+	// exit code and error of this run stay in its state until then.
+	if procCfg.Schedule != "" {
+		if p.StopRequested() {
 			return
-		case <-time.After(backoff):
 		}
+		if state.Status == StatusFailed {
+			pm.emitEvent(name, StatusRunning, StatusFailed, state.LastError)
+		}
+		if err := pm.armSchedule(name, p, state.Status); err != nil {
+			slog.Error("failed to reschedule", "process", name, "error", err)
+		}
+		return
+	}
 
-		// Check if we were stopped during the backoff.
-		currentState := p.State()
-		if currentState.Status != StatusRetrying {
+	if state.Status == StatusStopped || state.Status == StatusCompleted {
+		// Intentionally stopped, or exited cleanly without an "always" policy.
+		if p.StopRequested() || procCfg.Restart != config.RestartAlways {
+			if state.Status == StatusCompleted {
+				pm.emitEvent(name, StatusRunning, StatusCompleted, "")
+			}
 			return
 		}
+		p.ResetRetryCount()
+		pm.scheduleRestart(name, p, state.Status, 0, nextBackoff(0, effectiveRetry(procCfg)))
+		return
+	}
 
-		if err := pm.startSingle(name); err != nil {
-			slog.Error("retry failed", "process", name, "error", err)
-			// startSingle will emit events and the next monitor call will handle further retries.
-		}
+	// Process failed - check retry logic.
+	retryCfg := effectiveRetry(procCfg)
+	retryCount := state.RetryCount
+
+	// A crash after a long healthy run shouldn't count against earlier flaps.
+	if window := retryCfg.ResetAfter.Duration(); window > 0 && time.Since(state.StartedAt) >= window {
+		p.ResetRetryCount()
+		retryCount = 0
+	}
+
+	if shouldRetry(retryCount, retryCfg) {
+		backoff := nextBackoff(retryCount, retryCfg)
+		pm.scheduleRestart(name, p, StatusFailed, retryCount+1, backoff)
 	} else {
 		// Max retries exhausted - cascade failure.
 		p.SetStatus(StatusFailed)
@@ -432,16 +1197,65 @@ func (pm *ProcessManager) monitor(name string) {
 	}
 }
 
+// scheduleRestart marks a process as retrying, waits for backoff, then starts
+// it again unless it was stopped in the meantime.
+func (pm *ProcessManager) scheduleRestart(name string, p *ManagedProcess, oldStatus Status, attempt int, backoff time.Duration) {
+	nextRetry := time.Now().Add(backoff)
+	gen := p.ScheduleRetry(attempt, nextRetry)
+	pm.publish(StateEvent{
+		Name:        name,
+		OldState:    oldStatus,
+		NewState:    StatusRetrying,
+		Attempt:     attempt,
+		NextRetryAt: nextRetry,
+	})
+
+	slog.Info("scheduling retry", "process", name, "attempt", attempt, "backoff", backoff)
+
+	// Wait for backoff period.
+	select {
+	case <-pm.ctx.Done():
+		return
+	case <-time.After(backoff):
+	}
+
+	// Give up if the retry was cancelled by a stop, or superseded, during
+	// the backoff.
+	if !p.BeginRetry(gen) {
+		return
+	}
+
+	p.IncrementRestarts()
+	pm.publish(StateEvent{
+		Name:     name,
+		OldState: StatusRetrying,
+		NewState: StatusStarting,
+		Attempt:  attempt,
+	})
+	// StartRetry checks gen again, since a stop may arrive while waiting for
+	// a start slot.
+	err := pm.launch(name, p, func() (Run, error) {
+		return p.StartRetry(gen)
+	})
+	if err != nil {
+		slog.Error("retry failed", "process", name, "error", err)
+		// launch emits the failure and the next monitor call handles further retries.
+	}
+}
+
 // cascadeFailure marks all dependents of a failed process as failed.
 func (pm *ProcessManager) cascadeFailure(name string) {
-	dependents := pm.graph.Dependents(name)
+	dependents := pm.currentGraph().RequiredDependents(name)
 	for _, dep := range dependents {
 		pm.mu.RLock()
-		p := pm.processes[dep]
+		p, ok := pm.processes[dep]
 		pm.mu.RUnlock()
+		if !ok {
+			continue
+		}
 
 		state := p.State()
-		if state.Status == StatusRunning || state.Status == StatusStarting || state.Status == StatusRetrying {
+		if state.Status.IsRunning() || state.Status == StatusStarting || state.Status == StatusRetrying {
 			// Stop the dependent first.
 			pm.stopSingle(dep)
 		}
@@ -454,9 +1268,48 @@ func (pm *ProcessManager) cascadeFailure(name string) {
 	}
 }
 
-// waitForHealthy blocks until the named process has been running for depHealthDelay.
-func (pm *ProcessManager) waitForHealthy(name string) error {
-	timeout := 60 * time.Second
+// watchHealth polls the process's health check until it passes, then marks the
+// process healthy. It gives up if the process exits or the manager shuts down.
+func (pm *ProcessManager) watchHealth(name string, p *ManagedProcess) {
+	procCfg := pm.currentConfig().Processes[name]
+	hc := procCfg.HealthCheck
+	done := p.Wait()
+
+	for {
+		if p.State().Status != StatusRunning {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(pm.ctx, hc.Timeout.Duration())
+		err := checkHealth(ctx, hc, procCfg)
+		cancel()
+
+		if err == nil {
+			if p.MarkHealthy() {
+				pm.emitEvent(name, StatusRunning, StatusHealthy, "")
+			}
+			return
+		}
+		slog.Debug("health check failed", "process", name, "error", err)
+
+		select {
+		case <-pm.ctx.Done():
+			return
+		case <-done:
+			return
+		case <-time.After(hc.Interval.Duration()):
+		}
+	}
+}
+
+// waitForHealthy blocks until the named process satisfies condition. With
+// config.DependencyStarted it only needs to be running. Otherwise, processes
+// with a health check must reach StatusHealthy and others must have been
+// running for their startup delay.
+func (pm *ProcessManager) waitForHealthy(name, condition string) error {
+	cfg := pm.currentConfig()
+	timeout := cfg.StartTimeout(name)
+	poll := cfg.HealthPollInterval()
 	deadline := time.Now().Add(timeout)
 
 	for {
@@ -467,35 +1320,132 @@ func (pm *ProcessManager) waitForHealthy(name string) error {
 		}
 
 		if time.Now().After(deadline) {
-			return fmt.Errorf("timeout waiting for %s to become healthy", name)
+			return fmt.Errorf("timeout waiting for %s to become healthy after %s", name, timeout)
 		}
 
 		pm.mu.RLock()
-		p := pm.processes[name]
+		p, ok := pm.processes[name]
 		pm.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("dependency %s was removed from the config", name)
+		}
 
 		state := p.State()
 		if state.Status == StatusFailed {
 			return fmt.Errorf("dependency %s is in failed state", name)
 		}
-		if state.Status == StatusRunning && time.Since(state.StartedAt) >= depHealthDelay {
+		if isReady(state, pm.currentConfig().Processes[name], condition) {
 			return nil
 		}
 
-		time.Sleep(200 * time.Millisecond)
+		time.Sleep(poll)
 	}
 }
 
-func (pm *ProcessManager) emitEvent(name string, oldState, newState Status, errMsg string) {
+// isReady reports whether a process in state satisfies a dependency
+// condition: running for config.DependencyStarted, otherwise healthy if it has
+// a health check or running for its startup delay if not.
+func isReady(state ProcessState, procCfg config.Process, condition string) bool {
+	switch {
+	case condition == config.DependencyStarted:
+		return state.Status.IsRunning()
+	case procCfg.HealthCheck.Configured():
+		return state.Status == StatusHealthy
+	default:
+		return state.Status == StatusRunning && time.Since(state.StartedAt) >= procCfg.StartupDelayDuration()
+	}
+}
+
+// acquireStartSlot blocks until fewer than max_concurrent_starts processes
+// are starting, and returns a function that frees the slot. Without a limit
+// it returns immediately. It fails once shepherd is shutting down.
+func (pm *ProcessManager) acquireStartSlot() (release func(), err error) {
+	// Refusing here means Shutdown's StopAll can't miss a process launched
+	// behind it.
+	if err := pm.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if pm.startSlots == nil {
+		return func() {}, nil
+	}
 	select {
-	case pm.events <- StateEvent{
+	case pm.startSlots <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-pm.startSlots }) }, nil
+	case <-pm.ctx.Done():
+		return nil, pm.ctx.Err()
+	}
+}
+
+// releaseWhenReady frees a start slot once p is ready (see isReady) or has
+// exited, so a slot covers the whole startup rather than just the fork.
+func (pm *ProcessManager) releaseWhenReady(p *ManagedProcess, procCfg config.Process, release func()) {
+	defer release()
+
+	done := p.Wait()
+	ticker := time.NewTicker(pm.currentConfig().HealthPollInterval())
+	defer ticker.Stop()
+	for !isReady(p.State(), procCfg, "") {
+		select {
+		case <-done:
+			return
+		case <-pm.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enforceStartTimeout fails a process that isn't ready (see isReady) within
+// its start_timeout, so a hung startup doesn't leave its dependents waiting.
+// The process is stopped, marked failed, and its dependents fail with it.
+func (pm *ProcessManager) enforceStartTimeout(name string, p *ManagedProcess) {
+	cfg := pm.currentConfig()
+	procCfg := cfg.Processes[name]
+	timeout := cfg.StartTimeout(name)
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(cfg.HealthPollInterval())
+	defer ticker.Stop()
+
+	done := p.Wait()
+	for !isReady(p.State(), procCfg, "") {
+		select {
+		case <-done:
+			return
+		case <-pm.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-deadline.C:
+			errMsg := fmt.Sprintf("not ready after start_timeout of %s", timeout)
+			p.log.WriteString("[shepherd] Stopping: " + errMsg)
+			oldStatus := p.State().Status
+			if err := p.Stop(); err != nil {
+				slog.Warn("failed to stop timed out process", "process", name, "error", err)
+			}
+			p.SetStatus(StatusFailed)
+			p.SetError(errMsg)
+			pm.emitEvent(name, oldStatus, StatusFailed, errMsg)
+			pm.cascadeFailure(name)
+			return
+		}
+	}
+}
+
+func (pm *ProcessManager) emitEvent(name string, oldState, newState Status, errMsg string) {
+	pm.publish(StateEvent{
 		Name:     name,
 		OldState: oldState,
 		NewState: newState,
 		Error:    errMsg,
-	}:
-	default:
-		// Drop event if channel is full (shouldn't happen with buffer of 100).
-		slog.Warn("event channel full, dropping event", "process", name)
+	})
+}
+
+// publish sends ev to every subscriber.
+func (pm *ProcessManager) publish(ev StateEvent) {
+	pm.subsMu.Lock()
+	defer pm.subsMu.Unlock()
+	for sub := range pm.subs {
+		sub.push(ev)
 	}
 }
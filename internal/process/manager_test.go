@@ -4,6 +4,12 @@ package process
 
 import (
 	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -37,7 +43,7 @@ func testConfig() *config.Config {
 			},
 			"forward": {
 				Command:   "sleep 3600",
-				DependsOn: []string{"bastion"},
+				DependsOn: []config.Dependency{{Name: "bastion"}},
 			},
 			"service": {
 				Command: "sleep 3600",
@@ -65,6 +71,26 @@ func TestManager_StartSingleProcess(t *testing.T) {
 	assert.Equal(t, StatusRunning, states[0].Status)
 }
 
+func TestManager_GetState(t *testing.T) {
+	pm, err := NewProcessManager(context.Background(), testConfig())
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("bastion"))
+
+	st, ok := pm.GetState("bastion")
+	require.True(t, ok)
+	assert.Equal(t, "bastion", st.Name)
+	assert.Equal(t, StatusRunning, st.Status)
+
+	st, ok = pm.GetState("forward")
+	require.True(t, ok)
+	assert.Equal(t, StatusStopped, st.Status)
+
+	_, ok = pm.GetState("missing")
+	assert.False(t, ok)
+}
+
 func TestManager_StartWithDependency(t *testing.T) {
 	cfg := testConfig()
 
@@ -110,6 +136,28 @@ func TestManager_StopWithDependents(t *testing.T) {
 	}
 }
 
+func TestManager_StopProcessOnlyLeavesDependents(t *testing.T) {
+	cfg := testConfig()
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("forward"))
+	require.NoError(t, pm.StopProcessOnly("bastion"))
+
+	for _, s := range pm.GetAllStates() {
+		switch s.Name {
+		case "bastion":
+			assert.Equal(t, StatusStopped, s.Status)
+		case "forward":
+			assert.Equal(t, StatusRunning, s.Status)
+		}
+	}
+
+	assert.Error(t, pm.StopProcessOnly("missing"))
+}
+
 func TestManager_StartGroup(t *testing.T) {
 	cfg := testConfig()
 
@@ -117,8 +165,16 @@ func TestManager_StartGroup(t *testing.T) {
 	require.NoError(t, err)
 	defer pm.Shutdown()
 
-	err = pm.StartGroup("tunnels")
+	res, err := pm.StartGroup("tunnels")
 	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"bastion", "forward"}, res.Started)
+	assert.Empty(t, res.Skipped)
+	assert.Empty(t, res.Failed)
+
+	res, err = pm.StartGroup("tunnels")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"bastion", "forward"}, res.Skipped)
+	assert.Empty(t, res.Started)
 
 	states := pm.GetAllStates()
 	for _, s := range states {
@@ -128,14 +184,265 @@ func TestManager_StartGroup(t *testing.T) {
 	}
 }
 
+func TestManager_StartGroupAfterDependencyGroup(t *testing.T) {
+	delay := config.Duration(200 * time.Millisecond)
+	cfg := &config.Config{
+		Groups: map[string]config.Group{
+			"infra":   {Processes: []string{"db"}},
+			"backend": {Processes: []string{"api"}, DependsOn: []string{"infra"}},
+		},
+		Processes: map[string]config.Process{
+			"db":  {Command: "sleep 3600", StartupDelay: &delay},
+			"api": {Command: "sleep 3600"},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	res, err := pm.StartGroup("backend")
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+
+	db, _ := pm.GetState("db")
+	api, _ := pm.GetState("api")
+	assert.Equal(t, StatusRunning, db.Status, "the group it depends on is started too")
+	assert.Equal(t, StatusRunning, api.Status)
+	assert.GreaterOrEqual(t, api.StartedAt.Sub(db.StartedAt), 200*time.Millisecond,
+		"api waits for db to be ready")
+}
+
+func TestManager_StartAll(t *testing.T) {
+	delay := config.Duration(200 * time.Millisecond)
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"db":     {Command: "sleep 3600", StartupDelay: &delay},
+			"api":    {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "db"}}},
+			"worker": {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "db"}}},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	res, err := pm.StartAll()
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	assert.ElementsMatch(t, []string{"db", "api", "worker"}, res.Started)
+
+	db, _ := pm.GetState("db")
+	for _, name := range []string{"api", "worker"} {
+		s, _ := pm.GetState(name)
+		assert.Equal(t, StatusRunning, s.Status)
+		assert.GreaterOrEqual(t, s.StartedAt.Sub(db.StartedAt), 200*time.Millisecond,
+			"%s waits for db to be ready", name)
+	}
+}
+
+func TestManager_StopGroupStopsDependentsOnce(t *testing.T) {
+	cfg := &config.Config{
+		Groups: map[string]config.Group{
+			"backend": {Processes: []string{"api", "jobs"}},
+		},
+		Processes: map[string]config.Process{
+			"db":   {Command: "sleep 3600"},
+			"api":  {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "db"}}},
+			"jobs": {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "api"}}},
+			"web":  {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "api"}}},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	res, err := pm.StartAll()
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+
+	events, unsubscribe := pm.Subscribe()
+	defer unsubscribe()
+
+	require.NoError(t, pm.StopGroup("backend"))
+
+	var stopped []string
+	for len(stopped) < 3 {
+		select {
+		case ev := <-events:
+			if ev.NewState == StatusStopped {
+				stopped = append(stopped, ev.Name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("only saw stops for %v", stopped)
+		}
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event after group stop: %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.ElementsMatch(t, []string{"api", "jobs", "web"}, stopped, "each process is stopped once")
+	assert.Equal(t, "api", stopped[2], "dependents stop before api")
+
+	db, _ := pm.GetState("db")
+	assert.Equal(t, StatusRunning, db.Status, "dependencies outside the group stay up")
+
+	assert.Error(t, pm.StopGroup("nope"))
+}
+
+func TestManager_StartByTag(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"db":  {Command: "sleep 3600"},
+			"api": {Command: "sleep 3600", Tags: []string{"backend"}, DependsOn: []config.Dependency{{Name: "db"}}},
+			"web": {Command: "sleep 3600", Tags: []string{"frontend"}},
+		},
+	}
+	zero := config.Duration(0)
+	for name, proc := range cfg.Processes {
+		proc.StartupDelay = &zero
+		cfg.Processes[name] = proc
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	_, err = pm.Resolve("tag:nothing")
+	assert.ErrorContains(t, err, "unknown tag: nothing")
+
+	res, err := pm.StartByName("tag:backend")
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	assert.Equal(t, []string{"api", "db"}, pm.RunningNames(), "tagged processes start with their dependencies")
+}
+
+func TestStartPlan(t *testing.T) {
+	cfg := testConfig()
+
+	order, err := StartPlan(cfg, "full")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bastion", "service", "forward"}, order)
+
+	order, err = StartPlan(cfg, "forward")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bastion", "forward"}, order)
+
+	_, err = StartPlan(cfg, "missing")
+	assert.Error(t, err)
+}
+
+func TestManager_RestartGroup(t *testing.T) {
+	cfg := testConfig()
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	res, err := pm.StartStack("full")
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	pids := make(map[string]int)
+	for _, s := range pm.GetAllStates() {
+		pids[s.Name] = s.PID
+	}
+
+	require.NoError(t, pm.RestartGroup("tunnels"))
+
+	for _, s := range pm.GetAllStates() {
+		assert.Equal(t, StatusRunning, s.Status, "process %s should be running", s.Name)
+		if s.Name == "service" {
+			assert.Equal(t, pids[s.Name], s.PID, "service is not in the group")
+			continue
+		}
+		assert.NotEqual(t, pids[s.Name], s.PID, "process %s should have a new PID", s.Name)
+		assert.Equal(t, 1, s.Restarts)
+	}
+
+	assert.Error(t, pm.RestartGroup("nope"))
+}
+
+func TestManager_RestartStackBringsBackDependents(t *testing.T) {
+	cfg := testConfig()
+	cfg.Stacks["base"] = config.Stack{Groups: []string{"services"}}
+	cfg.Groups["services"] = config.Group{Processes: []string{"bastion"}}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("forward"))
+	before := make(map[string]int)
+	for _, s := range pm.GetAllStates() {
+		before[s.Name] = s.PID
+	}
+
+	require.NoError(t, pm.RestartStack("base"))
+
+	for _, s := range pm.GetAllStates() {
+		if s.Name == "service" {
+			assert.Equal(t, StatusStopped, s.Status)
+			continue
+		}
+		assert.Equal(t, StatusRunning, s.Status, "process %s should be running", s.Name)
+		assert.NotEqual(t, before[s.Name], s.PID, "process %s should have a new PID", s.Name)
+	}
+}
+
+func TestManager_RestartStackIncludesDependencyGroups(t *testing.T) {
+	cfg := &config.Config{
+		Stacks: map[string]config.Stack{"app": {Groups: []string{"backend"}}},
+		Groups: map[string]config.Group{
+			"infra":   {Processes: []string{"db"}},
+			"backend": {Processes: []string{"api"}, DependsOn: []string{"infra"}},
+		},
+		Processes: map[string]config.Process{
+			"db":  {Command: "sleep 3600"},
+			"api": {Command: "sleep 3600"},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	res, err := pm.StartStack("app")
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	db, _ := pm.GetState("db")
+
+	require.NoError(t, pm.RestartStack("app"))
+
+	after, _ := pm.GetState("db")
+	assert.Equal(t, StatusRunning, after.Status)
+	assert.NotEqual(t, db.PID, after.PID, "db is in a group the stack depends on, so it restarts too")
+	assert.Equal(t, 1, after.Restarts)
+}
+
+func TestManager_RunningNames(t *testing.T) {
+	cfg := testConfig()
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	assert.Empty(t, pm.RunningNames())
+
+	require.NoError(t, pm.StartProcess("forward"))
+	assert.Equal(t, []string{"bastion", "forward"}, pm.RunningNames())
+}
+
 func TestManager_StopAll(t *testing.T) {
 	cfg := testConfig()
 
 	pm, err := NewProcessManager(context.Background(), cfg)
 	require.NoError(t, err)
 
-	err = pm.StartStack("full")
+	res, err := pm.StartStack("full")
 	require.NoError(t, err)
+	require.NoError(t, res.Err())
 
 	err = pm.StopAll()
 	require.NoError(t, err)
@@ -254,3 +561,933 @@ func TestManager_GetLogBuffer(t *testing.T) {
 	buf = pm.GetLogBuffer("nonexistent")
 	assert.Nil(t, buf)
 }
+
+func TestManager_LogBufferSize(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"small": {Command: "true", LogBufferSize: 2},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	buf := pm.GetLogBuffer("small")
+	for _, line := range []string{"a", "b", "c"} {
+		buf.WriteString(line)
+	}
+	assert.Equal(t, []string{"b", "c"}, buf.All())
+}
+
+func TestManager_HealthCheckGatesDependents(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"db": {
+				Command: "sleep 3600",
+				HealthCheck: config.HealthCheck{
+					TCP:      ln.Addr().String(),
+					Interval: config.Duration(50 * time.Millisecond),
+					Timeout:  config.Duration(time.Second),
+				},
+			},
+			"app": {
+				Command:   "sleep 3600",
+				DependsOn: []config.Dependency{{Name: "db"}},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	start := time.Now()
+	err = pm.StartProcess("app")
+	require.NoError(t, err)
+
+	// A passing health check should release dependents well before the startup delay.
+	assert.Less(t, time.Since(start), config.DefaultStartupDelay)
+
+	for _, s := range pm.GetAllStates() {
+		switch s.Name {
+		case "db":
+			assert.Equal(t, StatusHealthy, s.Status)
+		case "app":
+			assert.Equal(t, StatusRunning, s.Status)
+		}
+	}
+}
+
+func TestManager_StartDiamondInLevels(t *testing.T) {
+	cfg := &config.Config{
+		Groups: map[string]config.Group{
+			"g": {Processes: []string{"b", "c"}},
+		},
+		Processes: map[string]config.Process{
+			"a": {Command: "sleep 3600"},
+			"b": {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "a"}}},
+			"c": {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "a"}}},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	start := time.Now()
+	res, err := pm.StartGroup("g")
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+
+	// b and c start together after a single wait on a's health delay.
+	assert.Less(t, time.Since(start), 2*config.DefaultStartupDelay)
+
+	for _, s := range pm.GetAllStates() {
+		assert.True(t, s.Status.IsRunning(), "process %s should be running", s.Name)
+	}
+}
+
+func TestManager_RestartAlwaysOnCleanExit(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"loop": {
+				Command: "true",
+				Restart: config.RestartAlways,
+				Retry: config.RetryConfig{
+					InitialBackoff:    config.Duration(50 * time.Millisecond),
+					MaxBackoff:        config.Duration(100 * time.Millisecond),
+					BackoffMultiplier: 1,
+				},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	events := pm.Events()
+	require.NoError(t, pm.StartProcess("loop"))
+
+	// Expect a restart to be scheduled after the clean exit.
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			if ev.Name == "loop" && ev.OldState == StatusCompleted && ev.NewState == StatusRetrying {
+				return
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for restart after clean exit")
+		}
+	}
+}
+
+func TestManager_CleanExitReportsCompleted(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"job":    {Command: "true"},
+			"server": {Command: "sleep 3600"},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	events, unsubscribe := pm.Subscribe()
+	defer unsubscribe()
+	require.NoError(t, pm.StartProcess("job"))
+
+	deadline := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case ev := <-events:
+			done = ev.Name == "job" && ev.NewState == StatusCompleted
+		case <-deadline:
+			t.Fatal("timed out waiting for job to complete")
+		}
+	}
+
+	require.NoError(t, pm.StartProcess("server"))
+	require.NoError(t, pm.StopProcess("server"))
+	server, _ := pm.GetState("server")
+	assert.Equal(t, StatusStopped, server.Status, "a stopped process isn't completed")
+}
+
+func TestManager_RestartNeverSkipsRetry(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"fail": {
+				Command: "exit 1",
+				Restart: config.RestartNever,
+				Retry: config.RetryConfig{
+					Enabled:           true,
+					MaxAttempts:       5,
+					InitialBackoff:    config.Duration(50 * time.Millisecond),
+					MaxBackoff:        config.Duration(100 * time.Millisecond),
+					BackoffMultiplier: 1,
+				},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	events := pm.Events()
+	require.NoError(t, pm.StartProcess("fail"))
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			require.NotEqual(t, StatusRetrying, ev.NewState, "restart: never should not retry")
+			if ev.Name == "fail" && ev.OldState == StatusFailed && ev.NewState == StatusFailed {
+				return
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for failure")
+		}
+	}
+}
+
+func TestManager_StartupDelayPerProcess(t *testing.T) {
+	zero := config.Duration(0)
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"fast": {Command: "sleep 3600", StartupDelay: &zero},
+			"app":  {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "fast"}}},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	start := time.Now()
+	require.NoError(t, pm.StartProcess("app"))
+	assert.Less(t, time.Since(start), config.DefaultStartupDelay)
+}
+
+func TestManager_OptionalDependencyFailureDoesNotBlock(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"cache": {Command: "exit 1"},
+			"app": {
+				Command:   "sleep 3600",
+				DependsOn: []config.Dependency{{Name: "cache", Optional: true}},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("app"))
+
+	for _, s := range pm.GetAllStates() {
+		switch s.Name {
+		case "cache":
+			assert.Equal(t, StatusFailed, s.Status)
+		case "app":
+			assert.Equal(t, StatusRunning, s.Status)
+		}
+	}
+}
+
+func TestManager_StartedConditionSkipsStartupDelay(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"bastion": {Command: "sleep 3600"},
+			"app": {
+				Command:   "sleep 3600",
+				DependsOn: []config.Dependency{{Name: "bastion", Condition: config.DependencyStarted}},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	start := time.Now()
+	require.NoError(t, pm.StartProcess("app"))
+	assert.Less(t, time.Since(start), config.DefaultStartupDelay)
+
+	for _, s := range pm.GetAllStates() {
+		assert.Equal(t, StatusRunning, s.Status, s.Name)
+	}
+}
+
+func TestManager_RetryResetAfterLongRun(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"flaky": {
+				Command: "sleep 0.2; exit 1",
+				Retry: config.RetryConfig{
+					Enabled:           true,
+					MaxAttempts:       1,
+					InitialBackoff:    config.Duration(10 * time.Millisecond),
+					MaxBackoff:        config.Duration(10 * time.Millisecond),
+					BackoffMultiplier: 1,
+					ResetAfter:        config.Duration(100 * time.Millisecond),
+				},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("flaky"))
+
+	// With max_attempts 1 the process would give up after its first retry;
+	// each run outlasts reset_after, so retries keep coming.
+	require.Eventually(t, func() bool {
+		return pm.GetAllStates()[0].Restarts >= 3
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestManager_StopAllParallelWithDeadline(t *testing.T) {
+	stubborn := config.Process{
+		Command:     "trap '' TERM; while true; do sleep 0.1; done",
+		StopTimeout: config.Duration(time.Minute),
+	}
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"a": stubborn,
+			"b": stubborn,
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+	pm.stopAllTimeout = 300 * time.Millisecond
+
+	require.NoError(t, pm.StartProcess("a"))
+	require.NoError(t, pm.StartProcess("b"))
+	time.Sleep(100 * time.Millisecond) // let the shells install their traps
+
+	start := time.Now()
+	require.NoError(t, pm.StopAll())
+	assert.Less(t, time.Since(start), 3*time.Second)
+
+	for _, s := range pm.GetAllStates() {
+		assert.Equal(t, StatusStopped, s.Status, s.Name)
+	}
+}
+
+func TestManager_RetryEventsScheduledAndBegun(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"fail": {
+				Command: "exit 1",
+				Retry: config.RetryConfig{
+					Enabled:           true,
+					MaxAttempts:       1,
+					InitialBackoff:    config.Duration(50 * time.Millisecond),
+					MaxBackoff:        config.Duration(50 * time.Millisecond),
+					BackoffMultiplier: 1,
+				},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	events, cancel := pm.Subscribe()
+	defer cancel()
+
+	require.NoError(t, pm.StartProcess("fail"))
+
+	var scheduled, begun *StateEvent
+	deadline := time.After(5 * time.Second)
+	for begun == nil {
+		select {
+		case ev := <-events:
+			if ev.Name != "fail" {
+				continue
+			}
+			switch {
+			case ev.NewState == StatusRetrying:
+				scheduled = &ev
+			case ev.OldState == StatusRetrying && ev.NewState == StatusStarting:
+				begun = &ev
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for retry events")
+		}
+	}
+
+	require.NotNil(t, scheduled)
+	assert.Equal(t, 1, scheduled.Attempt)
+	assert.False(t, scheduled.NextRetryAt.IsZero())
+	assert.Equal(t, 1, begun.Attempt)
+}
+
+func TestManager_StopDuringBackoffStaysStopped(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"fail": {
+				Command: "exit 1",
+				Retry: config.RetryConfig{
+					Enabled:           true,
+					MaxAttempts:       5,
+					InitialBackoff:    config.Duration(300 * time.Millisecond),
+					MaxBackoff:        config.Duration(300 * time.Millisecond),
+					BackoffMultiplier: 1,
+				},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	events, cancel := pm.Subscribe()
+	defer cancel()
+
+	require.NoError(t, pm.StartProcess("fail"))
+	deadline := time.After(5 * time.Second)
+	for retrying := false; !retrying; {
+		select {
+		case ev := <-events:
+			retrying = ev.Name == "fail" && ev.NewState == StatusRetrying
+		case <-deadline:
+			t.Fatal("timed out waiting for retry")
+		}
+	}
+	require.NoError(t, pm.StopProcess("fail"))
+
+	// Well past the backoff, the cancelled retry must not have started it.
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case ev := <-events:
+			if ev.Name == "fail" && ev.NewState != StatusStopped {
+				t.Fatalf("stopped process moved to %s", ev.NewState)
+			}
+		case <-timeout:
+			assert.Equal(t, StatusStopped, pm.processes["fail"].State().Status)
+			return
+		}
+	}
+}
+
+func TestProcess_RetryGeneration(t *testing.T) {
+	p, _ := newTestProcess("true")
+
+	stale := p.ScheduleRetry(1, time.Now())
+	_, cancelled := p.CancelRetry()
+	assert.True(t, cancelled)
+	assert.Equal(t, StatusStopped, p.State().Status)
+	assert.False(t, p.BeginRetry(stale), "a cancelled retry must not begin")
+
+	gen := p.ScheduleRetry(1, time.Now())
+	assert.False(t, p.BeginRetry(stale), "a superseded retry must not begin")
+	require.True(t, p.BeginRetry(gen))
+	assert.Equal(t, StatusStarting, p.State().Status)
+
+	// A stop while waiting for a start slot also wins.
+	p.CancelRetry()
+	run, err := p.StartRetry(gen)
+	require.NoError(t, err)
+	assert.Zero(t, run.ID)
+	assert.Equal(t, StatusStopped, p.State().Status)
+}
+
+func TestManager_KillProcess(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"wedged": {
+				Command:     "trap '' TERM; while true; do sleep 0.1; done",
+				StopTimeout: config.Duration(time.Minute),
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("wedged"))
+
+	start := time.Now()
+	require.NoError(t, pm.KillProcess("wedged"))
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, StatusStopped, pm.GetAllStates()[0].Status)
+}
+
+func TestManager_ApplyConfig(t *testing.T) {
+	cfg := testConfig()
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("service"))
+
+	newCfg := testConfig()
+	delete(newCfg.Processes, "service")
+	newCfg.Groups["services"] = config.Group{Processes: []string{"worker"}}
+	newCfg.Processes["worker"] = config.Process{Command: "sleep 3600"}
+	newCfg.Processes["bastion"] = config.Process{Command: "sleep 1800"}
+
+	require.NoError(t, pm.ApplyConfig(newCfg))
+	assert.Same(t, newCfg, pm.GetConfig())
+
+	states := make(map[string]ProcessState)
+	for _, s := range pm.GetAllStates() {
+		states[s.Name] = s
+	}
+	assert.NotContains(t, states, "service")
+	assert.Nil(t, pm.GetLogBuffer("service"))
+	assert.Equal(t, StatusStopped, states["worker"].Status)
+
+	res, err := pm.StartGroup("services")
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	require.NoError(t, pm.StartProcess("bastion"))
+	p := pm.processes["bastion"]
+	p.mu.Lock()
+	args := p.cmd.Args
+	p.mu.Unlock()
+	assert.Contains(t, args, "sleep 1800")
+}
+
+func TestManager_StartLimit(t *testing.T) {
+	delay := config.Duration(300 * time.Millisecond)
+	proc := config.Process{Command: "sleep 3600", StartupDelay: &delay}
+	cfg := &config.Config{
+		StartLimit: 1,
+		Groups: map[string]config.Group{
+			"all": {Processes: []string{"a", "b", "c"}},
+		},
+		Processes: map[string]config.Process{"a": proc, "b": proc, "c": proc},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	res, err := pm.StartGroup("all")
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+
+	var starts []time.Time
+	for _, s := range pm.GetAllStates() {
+		assert.Equal(t, StatusRunning, s.Status)
+		starts = append(starts, s.StartedAt)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	for i := 1; i < len(starts); i++ {
+		assert.GreaterOrEqual(t, starts[i].Sub(starts[i-1]), 250*time.Millisecond,
+			"each start should wait for the previous one to finish starting")
+	}
+}
+
+func TestManager_WatchRestartsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "src"), 0o755))
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"server": {
+				Command: "sleep 3600",
+				Watch: config.WatchConfig{
+					Paths:    []string{dir},
+					Patterns: []string{"*.go"},
+					Debounce: config.Duration(50 * time.Millisecond),
+				},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("server"))
+	// Give the watcher time to register before changing anything.
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644))
+	time.Sleep(200 * time.Millisecond)
+	state, _ := pm.GetState("server")
+	assert.Equal(t, 0, state.Restarts, "files not matching the patterns are ignored")
+
+	// A burst of changes in a subdirectory causes a single restart.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte{byte(i)}, 0o644))
+	}
+	require.Eventually(t, func() bool {
+		state, _ := pm.GetState("server")
+		return state.Restarts == 1 && state.Status == StatusRunning
+	}, 5*time.Second, 20*time.Millisecond)
+
+	time.Sleep(200 * time.Millisecond)
+	state, _ = pm.GetState("server")
+	assert.Equal(t, 1, state.Restarts)
+}
+
+func TestManager_WatchStartsCrashedProcess(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"server": {
+				Command: "echo run; sleep 0.1; exit 1",
+				Watch: config.WatchConfig{
+					Paths:    []string{dir},
+					Debounce: config.Duration(50 * time.Millisecond),
+				},
+			},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	runs := func() int {
+		return strings.Count(strings.Join(pm.GetLogBuffer("server").All(), "\n"), "run")
+	}
+
+	require.NoError(t, pm.StartProcess("server"))
+	require.Eventually(t, func() bool {
+		state, _ := pm.GetState("server")
+		return state.Status == StatusFailed
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// Fixing a file brings the crashed process back.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("fixed"), 0o644))
+	require.Eventually(t, func() bool { return runs() == 2 }, 5*time.Second, 20*time.Millisecond)
+
+	// Once stopped, changes are ignored.
+	require.NoError(t, pm.StopProcess("server"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("again"), 0o644))
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 2, runs())
+}
+
+func TestManager_ScheduledProcess(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"job": {Command: "echo ran; exit 3", Schedule: "@every 150ms"},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	runs := func() int {
+		return strings.Count(strings.Join(pm.GetLogBuffer("job").All(), "\n"), "ran")
+	}
+
+	require.NoError(t, pm.StartProcess("job"))
+	state, _ := pm.GetState("job")
+	assert.Equal(t, StatusScheduled, state.Status, "starting arms the schedule instead of running")
+	assert.False(t, state.NextRunAt.IsZero())
+
+	// Each run ends back in the scheduled state with its result kept.
+	require.Eventually(t, func() bool {
+		state, _ := pm.GetState("job")
+		return runs() >= 2 && state.Status == StatusScheduled
+	}, 5*time.Second, 20*time.Millisecond)
+	state, _ = pm.GetState("job")
+	assert.Equal(t, 3, state.ExitCode)
+	assert.NotEmpty(t, state.LastError)
+
+	require.NoError(t, pm.StopProcess("job"))
+	state, _ = pm.GetState("job")
+	assert.Equal(t, StatusStopped, state.Status)
+	before := runs()
+	time.Sleep(400 * time.Millisecond)
+	assert.Equal(t, before, runs(), "stopping cancels the schedule")
+}
+
+func TestManager_ApplyConfigCancelsRemovedSchedule(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"job": {Command: "touch " + marker, Schedule: "@every 150ms"},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("job"))
+	require.NoError(t, pm.ApplyConfig(&config.Config{Processes: map[string]config.Process{}}))
+
+	time.Sleep(400 * time.Millisecond)
+	assert.NoFileExists(t, marker, "a removed process never runs")
+}
+
+func TestManager_RestartProcessesReschedules(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"db":  {Command: "sleep 3600"},
+			"job": {Command: "true", Schedule: "@every 1h", DependsOn: []config.Dependency{{Name: "db"}}},
+		},
+	}
+
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	require.NoError(t, pm.StartProcess("job"))
+	before, _ := pm.GetState("job")
+	require.Equal(t, StatusScheduled, before.Status)
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, pm.RestartProcesses([]string{"db"}))
+	after, _ := pm.GetState("job")
+	assert.Equal(t, StatusScheduled, after.Status)
+	assert.True(t, after.NextRunAt.After(before.NextRunAt), "the pending run is re-armed")
+}
+
+func TestManager_TailLogs(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"talker": {Command: "sleep 0.2; echo hello; sleep 3600"},
+		},
+	}
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	_, _, err = pm.TailLogs("missing")
+	assert.EqualError(t, err, "unknown process: missing")
+
+	lines, unsubscribe, err := pm.TailLogs("talker")
+	require.NoError(t, err)
+	defer unsubscribe()
+	require.NoError(t, pm.StartProcess("talker"))
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case line := <-lines:
+			if strings.HasSuffix(line, "hello") {
+				return
+			}
+		case <-deadline:
+			t.Fatal("no live log line received")
+		}
+	}
+}
+
+func TestManager_StartTimeoutFailsHungStartup(t *testing.T) {
+	timeout := config.Duration(300 * time.Millisecond)
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"db": {
+				Command:      "sleep 3600",
+				StartTimeout: &timeout,
+				HealthCheck: config.HealthCheck{
+					Command:  "false",
+					Interval: config.Duration(50 * time.Millisecond),
+					Timeout:  config.Duration(time.Second),
+				},
+			},
+			"api": {
+				Command:   "sleep 3600",
+				DependsOn: []config.Dependency{{Name: "db"}},
+			},
+		},
+	}
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	start := time.Now()
+	err = pm.StartProcess("api")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waiting for dependency db")
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	require.Eventually(t, func() bool {
+		s, _ := pm.GetState("db")
+		return s.Status == StatusFailed
+	}, 5*time.Second, 50*time.Millisecond)
+	s, _ := pm.GetState("db")
+	assert.Equal(t, "not ready after start_timeout of 300ms", s.LastError)
+}
+
+func TestManager_StartGroupReportsPartialFailure(t *testing.T) {
+	cfg := &config.Config{
+		Groups: map[string]config.Group{
+			"backend": {Processes: []string{"db", "api", "cache"}},
+		},
+		Processes: map[string]config.Process{
+			"db":    {Command: "sleep 3600", WorkingDir: "/nonexistent/shepherd-test"},
+			"api":   {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "db"}}},
+			"cache": {Command: "sleep 3600"},
+		},
+	}
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	res, err := pm.StartGroup("backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache"}, res.Started)
+	assert.Equal(t, []string{"db", "api"}, res.FailedNames())
+	assert.Equal(t, "started 1, 2 failed: db, api", res.Summary())
+	require.Error(t, res.Err())
+	assert.Contains(t, res.Err().Error(), "2 processes failed to start (db, api)")
+}
+
+func TestManager_RestartWritesLogSeparator(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"app": {Command: "echo hello; sleep 3600"},
+		},
+	}
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	buf := pm.GetLogBuffer("app")
+	hellos := func() int { return strings.Count(strings.Join(buf.All(), "\n"), "hello") }
+	require.NoError(t, pm.StartProcess("app"))
+	require.Eventually(t, func() bool { return hellos() == 1 }, 5*time.Second, 20*time.Millisecond)
+	assert.NotContains(t, strings.Join(buf.All(), "\n"), "restarted at")
+
+	require.NoError(t, pm.RestartProcess("app"))
+	require.Eventually(t, func() bool { return hellos() == 2 }, 5*time.Second, 20*time.Millisecond)
+	var separators []int
+	for i, l := range buf.All() {
+		if strings.HasPrefix(l, "──── restarted at ") {
+			separators = append(separators, i)
+		}
+	}
+	require.Len(t, separators, 1)
+	assert.Contains(t, strings.Join(buf.All()[separators[0]:], "\n"), "hello")
+}
+
+func TestManager_LoadsLogFileTail(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "api.log")
+	require.NoError(t, os.WriteFile(logPath, []byte("first\nsecond\nthird\n"), 0o644))
+
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"api":    {Command: "sleep 3600", LogFile: logPath, LogBufferSize: 2},
+			"worker": {Command: "sleep 3600", LogFile: filepath.Join(dir, "missing.log")},
+		},
+	}
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	assert.Equal(t, []string{"second", "third"}, pm.GetLogBuffer("api").All())
+	assert.Equal(t, 0, pm.GetLogBuffer("worker").Len())
+
+	// The first run isn't a restart, whatever the log file holds.
+	require.NoError(t, pm.StartProcess("api"))
+	assert.NotContains(t, strings.Join(pm.GetLogBuffer("api").All(), "\n"), "restarted at")
+}
+
+func TestManager_SignalProcess(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"daemon": {Command: `trap 'echo got-usr1' USR1; echo ready; while true; do sleep 0.05; done`},
+		},
+	}
+	pm, err := NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	assert.Error(t, pm.SignalProcess("daemon", syscall.SIGUSR1), "not running yet")
+	assert.Error(t, pm.SignalProcess("nope", syscall.SIGUSR1))
+
+	require.NoError(t, pm.StartProcess("daemon"))
+	logs := pm.GetLogBuffer("daemon")
+	require.Eventually(t, func() bool {
+		return strings.Contains(strings.Join(logs.All(), "\n"), "ready")
+	}, 2*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, pm.SignalProcess("daemon", syscall.SIGUSR1))
+	require.Eventually(t, func() bool {
+		return strings.Contains(strings.Join(logs.All(), "\n"), "got-usr1")
+	}, 2*time.Second, 20*time.Millisecond)
+	assert.Contains(t, logs.All(), "[shepherd] Sent SIGUSR1")
+
+	state, _ := pm.GetState("daemon")
+	assert.Equal(t, StatusRunning, state.Status, "the process keeps running")
+}
+
+func TestManager_CancelledStartRollsBack(t *testing.T) {
+	// Nothing listens here, so db never becomes healthy.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"db": {
+				Command: "sleep 3600",
+				HealthCheck: config.HealthCheck{
+					TCP:      addr,
+					Interval: config.Duration(50 * time.Millisecond),
+					Timeout:  config.Duration(time.Second),
+				},
+			},
+			"app": {Command: "sleep 3600", DependsOn: []config.Dependency{{Name: "db"}}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pm, err := NewProcessManager(ctx, cfg)
+	require.NoError(t, err)
+	defer pm.Shutdown()
+
+	type outcome struct {
+		res *StartResult
+		err error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := pm.StartAll()
+		done <- outcome{res, err}
+	}()
+
+	require.Eventually(t, func() bool {
+		s, _ := pm.GetState("db")
+		return s.Status == StatusRunning
+	}, 2*time.Second, 20*time.Millisecond)
+	cancel()
+
+	var out outcome
+	select {
+	case out = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("start did not return after cancellation")
+	}
+	assert.ErrorIs(t, out.err, context.Canceled)
+	assert.Equal(t, []string{"db"}, out.res.RolledBack)
+	assert.Equal(t, []string{"app"}, out.res.Skipped)
+	assert.Empty(t, out.res.Started)
+	assert.Empty(t, out.res.Failed)
+
+	for _, name := range []string{"db", "app"} {
+		s, _ := pm.GetState(name)
+		assert.False(t, s.Status.IsRunning(), "%s is left %s", name, s.Status)
+	}
+}
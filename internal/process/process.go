@@ -2,10 +2,13 @@ package process
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"sync"
 	"syscall"
 	"time"
@@ -15,7 +18,12 @@ import (
 	"github.com/frontendtony/shepherd/internal/logging"
 )
 
-const stopTimeout = 10 * time.Second
+// defaultStopTimeout is how long Stop waits before escalating to SIGKILL when
+// the process config does not set stop_timeout.
+const defaultStopTimeout = 10 * time.Second
+
+// eofChar is the terminal's default EOF character (^D).
+const eofChar = 0x04
 
 // ManagedProcess wraps an exec.Cmd with lifecycle management and PTY output capture.
 type ManagedProcess struct {
@@ -26,8 +34,28 @@ type ManagedProcess struct {
 	mu    sync.Mutex
 	state ProcessState
 	cmd   *exec.Cmd
-	ptmx  *os.File // PTY master file descriptor (nil when using pipe fallback)
+	ptmx  *os.File     // PTY master file descriptor (nil when using pipe fallback)
+	size  *pty.Winsize // PTY size; nil leaves the default
 	done  chan struct{}
+
+	// stopRequested is set by Stop so a clean exit can be told apart from an
+	// intentional stop.
+	stopRequested bool
+
+	// runID counts the runs started so far; see Run.
+	runID uint64
+
+	// retryGen identifies the most recently scheduled retry. Cancelling or
+	// rescheduling bumps it, so a retry that wakes up late sees a stale
+	// generation and does nothing.
+	retryGen uint64
+}
+
+// Run identifies one execution of a process, so a watcher can tell whether
+// the process has been started again since.
+type Run struct {
+	ID   uint64          // 0 means nothing was started
+	Done <-chan struct{} // closed when this run exits
 }
 
 // NewManagedProcess creates a new managed process.
@@ -43,43 +71,118 @@ func NewManagedProcess(name string, cfg config.Process, logBuf *logging.RingBuff
 	}
 }
 
-// Start launches the process via PTY using sh -c.
+// Start launches the process via PTY, using sh -c for string commands or
+// exec'ing argv directly for list commands.
 // Falls back to pipe-based capture if PTY allocation fails.
 func (p *ManagedProcess) Start() error {
+	_, err := p.StartRun()
+	return err
+}
+
+// StartRun is like Start but also returns the run it began.
+func (p *ManagedProcess) StartRun() (Run, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.start(); err != nil {
+		return Run{}, err
+	}
+	return Run{ID: p.runID, Done: p.done}, nil
+}
+
+// StartRetry starts the process for the retry identified by gen, unless that
+// retry has been cancelled or superseded since, and returns the run it
+// began. The run's ID is 0 if the process wasn't started.
+func (p *ManagedProcess) StartRetry(gen uint64) (Run, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if gen != p.retryGen || p.state.Status != StatusStarting {
+		return Run{}, nil
+	}
+	if err := p.start(); err != nil {
+		return Run{}, err
+	}
+	return Run{ID: p.runID, Done: p.done}, nil
+}
+
+// HasRun reports whether the process has been started before.
+func (p *ManagedProcess) HasRun() bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	return p.runID > 0
+}
+
+// RunState returns the process's state once run has exited, and false if
+// the process has been started again since, in which case the state belongs
+// to the newer run.
+func (p *ManagedProcess) RunState(run Run) (ProcessState, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state, p.runID == run.ID
+}
 
-	if p.state.Status == StatusRunning {
+// start launches the process. The caller must hold p.mu.
+func (p *ManagedProcess) start() error {
+	if p.state.Status.IsRunning() {
 		return fmt.Errorf("process %s is already running", p.name)
 	}
 
 	p.state.Status = StatusStarting
+	p.stopRequested = false
 
-	cmd := p.buildCmd()
+	stdin, err := p.readStdin()
+	if err != nil {
+		p.state.Status = StatusFailed
+		p.state.LastError = err.Error()
+		p.log.WriteString(fmt.Sprintf("[shepherd] Failed to start: %s", err))
+		return fmt.Errorf("starting process %s: %w", p.name, err)
+	}
+
+	// Validate already checked the user and group, but the account may have
+	// been removed since, so resolve them afresh.
+	cred, err := p.config.Credential()
+	if err != nil {
+		p.state.Status = StatusFailed
+		p.state.LastError = err.Error()
+		p.log.WriteString(fmt.Sprintf("[shepherd] Failed to start: %s", err))
+		return fmt.Errorf("starting process %s: %w", p.name, err)
+	}
+
+	logFile := p.openLogFile()
+
+	cmd := p.buildCmd(cred)
 
 	// Try PTY first, fall back to pipes.
 	var reader io.Reader
 	var pipeWriter *io.PipeWriter
 
-	ptmx, err := pty.Start(cmd)
+	ptmx, err := pty.StartWithSize(cmd, p.size)
 	if err == nil {
 		p.ptmx = ptmx
 		reader = ptmx
+		if stdin != nil {
+			go writeTerminalInput(ptmx, stdin)
+		}
 	} else {
 		// Fallback: use pipes for stdout/stderr.
 		// Create a fresh Cmd since pty.Start may have already called cmd.Start().
 		p.log.WriteString(fmt.Sprintf("[shepherd] PTY unavailable, using pipes: %s", err))
-		cmd = p.buildCmd()
+		cmd = p.buildCmd(cred)
 		p.ptmx = nil
 		var pr *io.PipeReader
 		pr, pipeWriter = io.Pipe()
 		cmd.Stdout = pipeWriter
 		cmd.Stderr = pipeWriter
+		if stdin != nil {
+			cmd.Stdin = bytes.NewReader(stdin)
+		}
 		reader = pr
 
 		if err := cmd.Start(); err != nil {
 			pipeWriter.Close()
 			pr.Close()
+			if logFile != nil {
+				logFile.Close()
+			}
 			p.state.Status = StatusFailed
 			p.state.LastError = err.Error()
 			p.log.WriteString(fmt.Sprintf("[shepherd] Failed to start: %s", err))
@@ -87,17 +190,27 @@ func (p *ManagedProcess) Start() error {
 		}
 	}
 
+	// The process leads its own group, so this also reaches anything it has
+	// already forked.
+	if p.config.Nice != 0 {
+		if err := syscall.Setpriority(syscall.PRIO_PGRP, cmd.Process.Pid, p.config.Nice); err != nil {
+			p.log.WriteString(fmt.Sprintf("[shepherd] Cannot set nice %d: %s", p.config.Nice, err))
+		}
+	}
+
 	p.cmd = cmd
 	p.done = make(chan struct{})
+	p.runID++
 	p.state.Status = StatusRunning
 	p.state.PID = cmd.Process.Pid
+	p.state.PTY = p.ptmx != nil
 	p.state.StartedAt = time.Now()
 	p.state.StoppedAt = time.Time{}
 	p.state.LastError = ""
 	p.state.ExitCode = 0
 
-	// Read output into log buffer.
-	go p.readOutput(reader)
+	// Read output into log buffer (and log file, if configured).
+	go p.readOutput(reader, logFile)
 
 	// Monitor process exit.
 	go p.waitForExit(pipeWriter)
@@ -105,32 +218,45 @@ func (p *ManagedProcess) Start() error {
 	return nil
 }
 
-// Stop sends SIGTERM to the process group, then SIGKILL after timeout.
+// Stop sends the configured stop signal (SIGTERM by default) to the process
+// group, then SIGKILL after timeout.
 func (p *ManagedProcess) Stop() error {
 	p.mu.Lock()
 
-	if p.state.Status != StatusRunning && p.state.Status != StatusStarting {
+	if !p.state.Status.IsRunning() && p.state.Status != StatusStarting {
 		p.mu.Unlock()
 		return nil
 	}
 
 	p.state.Status = StatusStopping
+	p.stopRequested = true
 	cmd := p.cmd
 	done := p.done
+	cfg := p.config
 	p.mu.Unlock()
 
 	if cmd == nil || cmd.Process == nil {
 		return nil
 	}
 
-	// Send SIGTERM to process group.
-	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	sig, err := config.ParseSignal(cfg.StopSignal)
+	if err != nil {
+		sig = config.DefaultStopSignal
+	}
+
+	// Send the stop signal to the process group.
+	_ = syscall.Kill(-cmd.Process.Pid, sig)
+
+	timeout := cfg.StopTimeout.Duration()
+	if timeout <= 0 {
+		timeout = defaultStopTimeout
+	}
 
 	// Wait for exit or timeout.
 	select {
 	case <-done:
 		return nil
-	case <-time.After(stopTimeout):
+	case <-time.After(timeout):
 		// Force kill.
 		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
 		<-done
@@ -138,6 +264,49 @@ func (p *ManagedProcess) Stop() error {
 	}
 }
 
+// Kill immediately sends SIGKILL to the process group. The exit is recorded
+// as a requested stop, so it is not retried.
+func (p *ManagedProcess) Kill() {
+	p.mu.Lock()
+	if !p.state.Status.IsRunning() && p.state.Status != StatusStarting && p.state.Status != StatusStopping {
+		p.mu.Unlock()
+		return
+	}
+	p.state.Status = StatusStopping
+	p.stopRequested = true
+	cmd := p.cmd
+	p.mu.Unlock()
+
+	if cmd != nil && cmd.Process != nil {
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// Signal sends sig to the process group. It fails if the process isn't
+// running.
+func (p *ManagedProcess) Signal(sig syscall.Signal) error {
+	p.mu.Lock()
+	status := p.state.Status
+	cmd := p.cmd
+	p.mu.Unlock()
+
+	if !status.IsRunning() || cmd == nil || cmd.Process == nil {
+		return fmt.Errorf("process %s is not running", p.name)
+	}
+	return syscall.Kill(-cmd.Process.Pid, sig)
+}
+
+// SetSize sets the terminal size of the process's PTY, taking effect at once
+// if it is running on one and otherwise from the next start.
+func (p *ManagedProcess) SetSize(cols, rows int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.size = &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)}
+	if p.ptmx != nil && p.state.Status.IsRunning() {
+		_ = pty.Setsize(p.ptmx, p.size)
+	}
+}
+
 // Wait returns a channel that closes when the process exits.
 func (p *ManagedProcess) Wait() <-chan struct{} {
 	p.mu.Lock()
@@ -157,6 +326,14 @@ func (p *ManagedProcess) State() ProcessState {
 	return p.state
 }
 
+// StopRequested reports whether the most recent run was ended by Stop rather
+// than exiting on its own.
+func (p *ManagedProcess) StopRequested() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stopRequested
+}
+
 // Name returns the process name.
 func (p *ManagedProcess) Name() string {
 	return p.name
@@ -169,12 +346,88 @@ func (p *ManagedProcess) SetStatus(status Status) {
 	p.state.Status = status
 }
 
-// SetRetryState updates retry-related fields.
-func (p *ManagedProcess) SetRetryState(count int, nextRetry time.Time) {
+// MarkHealthy transitions a running process to healthy. It returns false if the
+// process is no longer running (e.g. it exited or is being stopped).
+func (p *ManagedProcess) MarkHealthy() bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.state.RetryCount = count
+	if p.state.Status != StatusRunning {
+		return false
+	}
+	p.state.Status = StatusHealthy
+	return true
+}
+
+// ScheduleRetry marks the process as retrying and returns the generation
+// that BeginRetry and StartRetry must be given for the retry to go ahead.
+// Any previously scheduled retry is superseded.
+func (p *ManagedProcess) ScheduleRetry(attempt int, nextRetry time.Time) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retryGen++
+	p.state.Status = StatusRetrying
+	p.state.RetryCount = attempt
 	p.state.NextRetryAt = nextRetry
+	return p.retryGen
+}
+
+// BeginRetry moves a retrying process to starting once its backoff is over.
+// It returns false if the retry identified by gen was cancelled or superseded,
+// or the process left the retrying state some other way.
+func (p *ManagedProcess) BeginRetry(gen uint64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if gen != p.retryGen || p.state.Status != StatusRetrying {
+		return false
+	}
+	p.state.Status = StatusStarting
+	return true
+}
+
+// ScheduleRun marks a process with a schedule as waiting for its next run at
+// next, and returns the generation that BeginRun and StartRetry must be
+// given for the run to go ahead. It shares the retry generation, so
+// CancelRetry also cancels a pending run.
+func (p *ManagedProcess) ScheduleRun(next time.Time) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retryGen++
+	p.state.Status = StatusScheduled
+	p.state.NextRunAt = next
+	return p.retryGen
+}
+
+// BeginRun moves a scheduled process to starting once its run is due. It
+// returns false if the run identified by gen was cancelled or superseded.
+func (p *ManagedProcess) BeginRun(gen uint64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if gen != p.retryGen || p.state.Status != StatusScheduled {
+		return false
+	}
+	p.state.Status = StatusStarting
+	p.state.NextRunAt = time.Time{}
+	return true
+}
+
+// CancelRetry invalidates any scheduled retry or run. If one was pending,
+// either in its backoff, waiting for its scheduled time, or waiting to start,
+// the process is marked stopped and the previous status is returned with
+// true.
+func (p *ManagedProcess) CancelRetry() (Status, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retryGen++
+	// Start holds p.mu until the process is running or has failed, so a
+	// starting status seen here always belongs to a retry that hasn't
+	// launched yet.
+	old := p.state.Status
+	if old != StatusRetrying && old != StatusStarting && old != StatusScheduled {
+		return old, false
+	}
+	p.state.Status = StatusStopped
+	p.state.NextRunAt = time.Time{}
+	return old, true
 }
 
 // SetError sets the last error message.
@@ -184,6 +437,33 @@ func (p *ManagedProcess) SetError(err string) {
 	p.state.LastError = err
 }
 
+// SetUsage records the latest resource usage sample.
+func (p *ManagedProcess) SetUsage(memoryBytes uint64, cpuPercent float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state.PID == 0 {
+		return
+	}
+	p.state.MemoryBytes = memoryBytes
+	p.state.CPUPercent = cpuPercent
+}
+
+// SetConfig replaces the process definition. It takes effect the next time
+// the process is started.
+func (p *ManagedProcess) SetConfig(cfg config.Process) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config = cfg
+}
+
+// IncrementRestarts records that the process is being restarted. Unlike the
+// retry count, this total is never reset.
+func (p *ManagedProcess) IncrementRestarts() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state.Restarts++
+}
+
 // ResetRetryCount resets the retry counter.
 func (p *ManagedProcess) ResetRetryCount() {
 	p.mu.Lock()
@@ -192,14 +472,71 @@ func (p *ManagedProcess) ResetRetryCount() {
 	p.state.NextRetryAt = time.Time{}
 }
 
-func (p *ManagedProcess) readOutput(r io.Reader) {
+// maxOutputLine is the longest line readOutput passes on whole. Longer lines
+// are split into chunks of this size.
+const maxOutputLine = 256 * 1024
+
+// readOutput copies process output line by line into the ring buffer and,
+// when logFile is non-nil, appends it to the file. The file is closed once the
+// output stream ends.
+func (p *ManagedProcess) readOutput(r io.Reader, logFile *os.File) {
+	if logFile != nil {
+		defer logFile.Close()
+	}
 	scanner := bufio.NewScanner(r)
-	scanner.Buffer(make([]byte, 64*1024), 256*1024)
+	scanner.Buffer(make([]byte, 64*1024), maxOutputLine)
+	scanner.Split(scanLongLines)
+	warned := false
 	for scanner.Scan() {
-		p.log.Write(append(scanner.Bytes(), '\n'))
+		tok := scanner.Bytes()
+		split := len(tok) == maxOutputLine
+		line := append(tok, '\n')
+		p.log.Write(line)
+		if logFile != nil {
+			_, _ = logFile.Write(line)
+		}
+		if split && !warned {
+			warned = true
+			p.log.WriteString(fmt.Sprintf("[shepherd] Output line longer than %dKB; splitting long lines into chunks", maxOutputLine/1024))
+		}
+	}
+	// A PTY reports EIO once the process side closes, and waitForExit may
+	// close the PTY first; both just mean the output has ended.
+	if err := scanner.Err(); err != nil && !errors.Is(err, syscall.EIO) && !errors.Is(err, os.ErrClosed) {
+		p.log.WriteString(fmt.Sprintf("[shepherd] Stopped reading output: %s", err))
 	}
 }
 
+// scanLongLines is bufio.ScanLines, except that a line with no newline within
+// maxOutputLine bytes is returned in chunks instead of failing the scan with
+// bufio.ErrTooLong, which would silently drop all later output.
+func scanLongLines(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	if advance == 0 && token == nil && err == nil && len(data) >= maxOutputLine {
+		return maxOutputLine, data[:maxOutputLine], nil
+	}
+	return advance, token, err
+}
+
+// openLogFile opens the configured log_file for appending, creating parent
+// directories as needed. It returns nil if no log file is configured or it
+// cannot be opened; failures are reported in the ring buffer.
+func (p *ManagedProcess) openLogFile() *os.File {
+	if p.config.LogFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p.config.LogFile), 0o755); err != nil {
+		p.log.WriteString(fmt.Sprintf("[shepherd] Cannot create log directory: %s", err))
+		return nil
+	}
+	f, err := os.OpenFile(p.config.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		p.log.WriteString(fmt.Sprintf("[shepherd] Cannot open log file: %s", err))
+		return nil
+	}
+	return f
+}
+
 // waitForExit waits for the process to exit and updates state.
 // If pw is non-nil (pipe fallback mode), it closes the pipe writer after cmd.Wait().
 func (p *ManagedProcess) waitForExit(pw *io.PipeWriter) {
@@ -218,39 +555,100 @@ func (p *ManagedProcess) waitForExit(pw *io.PipeWriter) {
 
 	p.state.StoppedAt = time.Now()
 	p.state.PID = 0
-
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			p.state.ExitCode = exitErr.ExitCode()
-		}
-		if p.state.Status == StatusStopping {
-			p.state.Status = StatusStopped
-		} else {
-			p.state.Status = StatusFailed
-			p.state.LastError = err.Error()
-			p.log.WriteString(fmt.Sprintf("[shepherd] Process exited with error: %s", err))
-		}
-	} else {
+	p.state.MemoryBytes = 0
+	p.state.CPUPercent = 0
+
+	exited := err == nil
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		p.state.ExitCode = exitErr.ExitCode()
+		exited = true
+	} else if err == nil {
 		p.state.ExitCode = 0
+	}
+
+	switch {
+	case p.state.Status == StatusStopping:
 		p.state.Status = StatusStopped
+	case exited && p.config.IsSuccessExit(p.state.ExitCode):
+		// Exit code 0, or another code listed in success_exit_codes.
+		p.state.Status = StatusCompleted
+	default:
+		if err == nil {
+			err = fmt.Errorf("exit status %d", p.state.ExitCode)
+		}
+		p.state.Status = StatusFailed
+		p.state.LastError = err.Error()
+		p.log.WriteString(fmt.Sprintf("[shepherd] Process exited with error: %s", err))
 	}
 
 	close(p.done)
 }
 
-func (p *ManagedProcess) buildCmd() *exec.Cmd {
-	cmd := exec.Command("sh", "-c", p.config.Command)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+// readStdin returns the configured stdin input, or nil if none is set.
+func (p *ManagedProcess) readStdin() ([]byte, error) {
+	switch {
+	case p.config.Stdin != "":
+		return []byte(p.config.Stdin), nil
+	case p.config.StdinFile != "":
+		data, err := os.ReadFile(p.config.StdinFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin_file: %w", err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// writeTerminalInput types data into the PTY and then sends EOF. The terminal
+// only treats ^D as end of input at the start of a line, so a trailing
+// partial line is flushed with one ^D before the final one.
+func writeTerminalInput(ptmx *os.File, data []byte) {
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, eofChar)
+	}
+	data = append(data, eofChar)
+	// The PTY may already be closed if the process exited without reading.
+	_, _ = ptmx.Write(data)
+}
+
+func (p *ManagedProcess) buildCmd(cred *syscall.Credential) *exec.Cmd {
+	var cmd *exec.Cmd
+	if len(p.config.Args) > 0 {
+		cmd = exec.Command(p.config.Args[0], p.config.Args[1:]...)
+	} else {
+		cmd = exec.Command(shellFor(p.config), "-c", p.config.Command)
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Credential: cred}
 	if p.config.WorkingDir != "" {
 		cmd.Dir = p.config.WorkingDir
 	}
-	cmd.Env = buildEnv(p.config.Env)
+	cmd.Env = buildEnv(p.config)
 	return cmd
 }
 
-func buildEnv(extra map[string]string) []string {
-	env := os.Environ()
-	for k, v := range extra {
+// shellFor returns the shell that runs proc's string commands.
+func shellFor(proc config.Process) string {
+	if proc.Shell == "" {
+		return config.DefaultShell
+	}
+	return proc.Shell
+}
+
+// buildEnv returns a process's environment: the parent's, or with clear_env
+// or inherit_env only the inherit_env variables from it, followed by the
+// process's own env.
+func buildEnv(proc config.Process) []string {
+	var env []string
+	if proc.IsolatedEnv() {
+		for _, k := range proc.InheritEnv {
+			if v, ok := os.LookupEnv(k); ok {
+				env = append(env, k+"="+v)
+			}
+		}
+	} else {
+		env = os.Environ()
+	}
+	for k, v := range proc.Env {
 		env = append(env, fmt.Sprintf("%s=%s", k, v))
 	}
 	return env
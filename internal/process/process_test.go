@@ -3,6 +3,11 @@
 package process
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,7 +43,7 @@ func TestProcess_StartAndExit(t *testing.T) {
 	}
 
 	state = proc.State()
-	assert.Equal(t, StatusStopped, state.Status)
+	assert.Equal(t, StatusCompleted, state.Status, "a clean exit isn't a stop")
 	assert.Equal(t, 0, state.ExitCode)
 	assert.NotZero(t, state.StoppedAt)
 
@@ -64,6 +69,33 @@ func TestProcess_StartAndStop(t *testing.T) {
 	assert.Equal(t, StatusStopped, state.Status)
 }
 
+func TestProcess_ReportsPTY(t *testing.T) {
+	proc, buf := newTestProcess("sleep 3600")
+	require.NoError(t, proc.Start())
+	defer proc.Stop()
+
+	// Sandboxes without /dev/ptmx fall back to pipes and say so in the log.
+	fellBack := strings.Contains(strings.Join(buf.All(), "\n"), "PTY unavailable")
+	assert.Equal(t, !fellBack, proc.State().PTY)
+}
+
+func TestProcess_SetSize(t *testing.T) {
+	proc, buf := newTestProcess("stty size")
+	proc.SetSize(120, 30)
+	require.NoError(t, proc.Start())
+
+	select {
+	case <-proc.Wait():
+	case <-time.After(5 * time.Second):
+		t.Fatal("process did not exit in time")
+	}
+	if !proc.State().PTY {
+		t.Skip("PTY unavailable")
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Contains(t, strings.Join(buf.All(), "\n"), "30 120")
+}
+
 func TestProcess_FailedCommand(t *testing.T) {
 	proc, _ := newTestProcess("exit 42")
 
@@ -82,6 +114,27 @@ func TestProcess_FailedCommand(t *testing.T) {
 	assert.NotEmpty(t, state.LastError)
 }
 
+func TestProcess_SuccessExitCodes(t *testing.T) {
+	buf := logging.NewRingBuffer(100)
+	proc := NewManagedProcess("test", config.Process{
+		Command:     "exit 2",
+		SuccessExit: []int{0, 2},
+	}, buf)
+
+	require.NoError(t, proc.Start())
+
+	select {
+	case <-proc.Wait():
+	case <-time.After(5 * time.Second):
+		t.Fatal("process did not exit in time")
+	}
+
+	state := proc.State()
+	assert.Equal(t, StatusCompleted, state.Status)
+	assert.Equal(t, 2, state.ExitCode)
+	assert.Empty(t, state.LastError)
+}
+
 func TestProcess_NonexistentCommand(t *testing.T) {
 	proc, _ := newTestProcess("this_command_does_not_exist_12345")
 
@@ -219,3 +272,225 @@ func TestProcess_EnvVars(t *testing.T) {
 	}
 	assert.True(t, found, "expected env var in output, got: %v", lines)
 }
+
+func TestBuildEnv_Isolated(t *testing.T) {
+	t.Setenv("SHEPHERD_KEEP", "kept")
+	t.Setenv("SHEPHERD_DROP", "dropped")
+
+	env := buildEnv(config.Process{Env: map[string]string{"OWN": "1"}})
+	assert.Contains(t, env, "SHEPHERD_DROP=dropped")
+	assert.Contains(t, env, "OWN=1")
+
+	env = buildEnv(config.Process{
+		InheritEnv: []string{"SHEPHERD_KEEP", "SHEPHERD_UNSET"},
+		Env:        map[string]string{"OWN": "1"},
+	})
+	assert.ElementsMatch(t, []string{"SHEPHERD_KEEP=kept", "OWN=1"}, env)
+
+	env = buildEnv(config.Process{ClearEnv: true, Env: map[string]string{"OWN": "1"}})
+	assert.Equal(t, []string{"OWN=1"}, env)
+}
+
+func TestProcess_CustomStopSignal(t *testing.T) {
+	buf := logging.NewRingBuffer(100)
+	proc := NewManagedProcess("test", config.Process{
+		Command:    "trap '' TERM; trap 'exit 0' INT; while true; do sleep 0.1; done",
+		StopSignal: "SIGINT",
+	}, buf)
+
+	err := proc.Start()
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	err = proc.Stop()
+	require.NoError(t, err)
+
+	// SIGTERM is ignored, so a fast stop means SIGINT was delivered.
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, StatusStopped, proc.State().Status)
+}
+
+func TestProcess_StopTimeoutEscalatesToKill(t *testing.T) {
+	buf := logging.NewRingBuffer(100)
+	proc := NewManagedProcess("test", config.Process{
+		Command:     "trap '' TERM; while true; do sleep 0.1; done",
+		StopTimeout: config.Duration(300 * time.Millisecond),
+	}, buf)
+
+	err := proc.Start()
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	err = proc.Stop()
+	require.NoError(t, err)
+
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 300*time.Millisecond)
+	assert.Less(t, elapsed, defaultStopTimeout)
+}
+
+func TestProcess_Shell(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not installed")
+	}
+	buf := logging.NewRingBuffer(100)
+	proc := NewManagedProcess("test", config.Process{
+		Command: `if [[ -n "$BASH_VERSION" ]]; then echo in-bash; fi`,
+		Shell:   "bash",
+	}, buf)
+
+	require.NoError(t, proc.Start())
+	select {
+	case <-proc.Wait():
+	case <-time.After(5 * time.Second):
+		t.Fatal("process did not exit in time")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, StatusCompleted, proc.State().Status)
+	assert.Contains(t, strings.Join(buf.All(), "\n"), "in-bash")
+}
+
+func TestProcess_ArgvCommand(t *testing.T) {
+	buf := logging.NewRingBuffer(100)
+	proc := NewManagedProcess("test", config.Process{
+		Args: []string{"echo", "it's $NOT_EXPANDED"},
+	}, buf)
+
+	err := proc.Start()
+	require.NoError(t, err)
+
+	select {
+	case <-proc.Wait():
+	case <-time.After(5 * time.Second):
+		t.Fatal("process did not exit in time")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	lines := buf.All()
+	found := false
+	for _, l := range lines {
+		if containsStr(l, "it's $NOT_EXPANDED") {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "expected literal argument in output, got: %v", lines)
+}
+
+func TestProcess_LogFile(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "nested", "proc.log")
+	require.NoError(t, os.MkdirAll(filepath.Dir(logPath), 0o755))
+	require.NoError(t, os.WriteFile(logPath, []byte("previous run\n"), 0o644))
+
+	buf := logging.NewRingBuffer(100)
+	proc := NewManagedProcess("test", config.Process{
+		Command: "echo to-file",
+		LogFile: logPath,
+	}, buf)
+
+	err := proc.Start()
+	require.NoError(t, err)
+
+	select {
+	case <-proc.Wait():
+	case <-time.After(5 * time.Second):
+		t.Fatal("process did not exit in time")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "previous run\n")
+	assert.Contains(t, string(data), "to-file")
+}
+
+func TestProcess_Stdin(t *testing.T) {
+	stdinFile := filepath.Join(t.TempDir(), "input")
+	require.NoError(t, os.WriteFile(stdinFile, []byte("from\nfile\n"), 0o644))
+
+	tests := []struct {
+		name string
+		cfg  config.Process
+		want string
+	}{
+		{"string", config.Process{Stdin: "one\ntwo"}, "got:one:two"},
+		{"file", config.Process{StdinFile: stdinFile}, "got:from:file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// cat only returns once stdin is closed.
+			tt.cfg.Command = "read a; read b; cat >/dev/null; echo got:$a:$b"
+			buf := logging.NewRingBuffer(100)
+			proc := NewManagedProcess("test", tt.cfg, buf)
+
+			require.NoError(t, proc.Start())
+			select {
+			case <-proc.Wait():
+			case <-time.After(5 * time.Second):
+				t.Fatal("process did not see end of stdin")
+			}
+
+			time.Sleep(50 * time.Millisecond)
+			assert.Contains(t, strings.Join(buf.All(), "\n"), tt.want)
+		})
+	}
+}
+
+func TestProcess_StdinFileMissing(t *testing.T) {
+	buf := logging.NewRingBuffer(100)
+	proc := NewManagedProcess("test", config.Process{
+		Command:   "cat",
+		StdinFile: filepath.Join(t.TempDir(), "missing"),
+	}, buf)
+
+	require.Error(t, proc.Start())
+	assert.Equal(t, StatusFailed, proc.State().Status)
+}
+
+func TestProcess_ReadOutputLongLine(t *testing.T) {
+	p, buf := newTestProcess("true")
+	long := strings.Repeat("x", maxOutputLine*2+10)
+	p.readOutput(strings.NewReader("before\n"+long+"\nafter\n"), nil)
+
+	var texts []string
+	for _, e := range buf.Entries(0) {
+		texts = append(texts, e.Text)
+	}
+	require.Len(t, texts, 6)
+	assert.Equal(t, "before", texts[0])
+	assert.Contains(t, texts[2], "splitting long lines", "marker follows the first chunk")
+	assert.Equal(t, strings.Repeat("x", 10), texts[4])
+	assert.Equal(t, "after", texts[5], "output after the long line is not lost")
+}
+
+func TestProcess_Nice(t *testing.T) {
+	out, err := exec.Command("nice").Output()
+	if err != nil {
+		t.Skip("nice not installed")
+	}
+	var base int
+	fmt.Sscan(string(out), &base)
+	if base > 14 {
+		t.Skip("already running at a low priority")
+	}
+
+	buf := logging.NewRingBuffer(100)
+	proc := NewManagedProcess("test", config.Process{
+		// The pause lets Start lower the priority before it is read.
+		Command: "sleep 0.2; echo nice=$(nice)",
+		Nice:    5,
+	}, buf)
+
+	require.NoError(t, proc.Start())
+	select {
+	case <-proc.Wait():
+	case <-time.After(5 * time.Second):
+		t.Fatal("process did not exit in time")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Contains(t, strings.Join(buf.All(), "\n"), fmt.Sprintf("nice=%d", base+5))
+}
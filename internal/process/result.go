@@ -0,0 +1,59 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StartResult reports what a multi-process start did with each process it
+// covered, in start order.
+type StartResult struct {
+	Started    []string       // started, or had their schedule armed, by this call
+	Skipped    []string       // already running, or not started because shepherd shut down
+	RolledBack []string       // started by this call, then stopped because shepherd shut down
+	Failed     []StartFailure // could not be started
+}
+
+// StartFailure is a process that could not be started, and why.
+type StartFailure struct {
+	Name string
+	Err  error
+}
+
+// Err returns nil if every process started or was skipped. Otherwise it
+// returns the failure, or a summary wrapping the first one if several failed.
+func (r *StartResult) Err() error {
+	switch len(r.Failed) {
+	case 0:
+		return nil
+	case 1:
+		return r.Failed[0].Err
+	}
+	return fmt.Errorf("%d processes failed to start (%s); first: %w",
+		len(r.Failed), strings.Join(r.FailedNames(), ", "), r.Failed[0].Err)
+}
+
+// FailedNames returns the names of the processes that failed to start.
+func (r *StartResult) FailedNames() []string {
+	names := make([]string, len(r.Failed))
+	for i, f := range r.Failed {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Summary describes the result in one line, e.g.
+// "started 3, 1 skipped, 2 failed: api, worker".
+func (r *StartResult) Summary() string {
+	parts := []string{fmt.Sprintf("started %d", len(r.Started))}
+	if len(r.Skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", len(r.Skipped)))
+	}
+	if len(r.RolledBack) > 0 {
+		parts = append(parts, fmt.Sprintf("%d rolled back", len(r.RolledBack)))
+	}
+	if len(r.Failed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed: %s", len(r.Failed), strings.Join(r.FailedNames(), ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
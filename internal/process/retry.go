@@ -9,17 +9,20 @@ import (
 )
 
 // nextBackoff calculates the backoff duration for a given retry attempt.
-// Uses exponential backoff with +/- 10% jitter.
+// Uses exponential backoff, or initial_backoff every time in fixed mode, with
+// +/- jitter (10% by default).
 func nextBackoff(attempt int, cfg config.RetryConfig) time.Duration {
-	base := float64(cfg.InitialBackoff.Duration()) * math.Pow(cfg.BackoffMultiplier, float64(attempt))
+	base := float64(cfg.InitialBackoff.Duration())
+	if cfg.Mode != config.RetryFixed {
+		base *= math.Pow(cfg.BackoffMultiplier, float64(attempt))
 
-	maxBackoff := float64(cfg.MaxBackoff.Duration())
-	if base > maxBackoff {
-		base = maxBackoff
+		maxBackoff := float64(cfg.MaxBackoff.Duration())
+		if base > maxBackoff {
+			base = maxBackoff
+		}
 	}
 
-	// Add jitter: +/- 10%.
-	jitter := base * 0.1
+	jitter := base * cfg.JitterFraction()
 	base = base - jitter + (rand.Float64() * 2 * jitter)
 
 	return time.Duration(base)
@@ -36,3 +39,16 @@ func shouldRetry(attempt int, cfg config.RetryConfig) bool {
 	}
 	return attempt < cfg.MaxAttempts
 }
+
+// effectiveRetry returns the retry config adjusted for the process's restart
+// policy: "never" disables retries and "always" forces them on.
+func effectiveRetry(proc config.Process) config.RetryConfig {
+	cfg := proc.Retry
+	switch proc.Restart {
+	case config.RestartNever:
+		cfg.Enabled = false
+	case config.RestartAlways:
+		cfg.Enabled = true
+	}
+	return cfg
+}
@@ -67,6 +67,40 @@ func TestNextBackoff_Jitter(t *testing.T) {
 	assert.Greater(t, len(values), 1, "expected jitter to produce varying values")
 }
 
+func TestNextBackoff_ConfiguredJitter(t *testing.T) {
+	none, wide := 0.0, 0.5
+	cfg := config.RetryConfig{
+		InitialBackoff:    config.Duration(10 * time.Second),
+		MaxBackoff:        config.Duration(60 * time.Second),
+		BackoffMultiplier: 2,
+		Jitter:            &none,
+	}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, 20*time.Second, nextBackoff(1, cfg))
+	}
+
+	cfg.Jitter = &wide
+	for i := 0; i < 100; i++ {
+		assert.InDelta(t, 10*time.Second, nextBackoff(0, cfg), float64(5*time.Second))
+	}
+}
+
+func TestNextBackoff_FixedMode(t *testing.T) {
+	none := 0.0
+	cfg := config.RetryConfig{
+		Mode:              config.RetryFixed,
+		InitialBackoff:    config.Duration(5 * time.Second),
+		MaxBackoff:        config.Duration(time.Second), // ignored in fixed mode
+		BackoffMultiplier: 3,
+		Jitter:            &none,
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		assert.Equal(t, 5*time.Second, nextBackoff(attempt, cfg))
+	}
+}
+
 func TestShouldRetry_Disabled(t *testing.T) {
 	cfg := config.RetryConfig{
 		Enabled:     false,
@@ -100,3 +134,17 @@ func TestShouldRetry_InfiniteRetries(t *testing.T) {
 	assert.True(t, shouldRetry(100, cfg))
 	assert.True(t, shouldRetry(999999, cfg))
 }
+
+func TestEffectiveRetry_RestartPolicy(t *testing.T) {
+	proc := config.Process{Retry: config.RetryConfig{Enabled: true, MaxAttempts: 3}}
+
+	proc.Restart = config.RestartOnFailure
+	assert.True(t, effectiveRetry(proc).Enabled)
+
+	proc.Restart = config.RestartNever
+	assert.False(t, effectiveRetry(proc).Enabled)
+
+	proc.Restart = config.RestartAlways
+	proc.Retry.Enabled = false
+	assert.True(t, effectiveRetry(proc).Enabled)
+}
@@ -0,0 +1,59 @@
+package process
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/frontendtony/shepherd/internal/config"
+)
+
+// armSchedule waits for the next run of a process with a schedule instead of
+// starting it now. oldStatus is reported as the status the process leaves.
+func (pm *ProcessManager) armSchedule(name string, p *ManagedProcess, oldStatus Status) error {
+	procCfg := pm.currentConfig().Processes[name]
+	sched, err := config.ParseSchedule(procCfg.Schedule)
+	if err != nil {
+		return fmt.Errorf("scheduling %s: %w", name, err)
+	}
+	next := sched.Next(time.Now())
+	if next.IsZero() {
+		return fmt.Errorf("scheduling %s: schedule %q never fires", name, procCfg.Schedule)
+	}
+
+	gen := p.ScheduleRun(next)
+	pm.emitEvent(name, oldStatus, StatusScheduled, "")
+	slog.Info("scheduled run", "process", name, "at", next)
+
+	go pm.runScheduled(name, p, gen, next)
+	return nil
+}
+
+// runScheduled starts a scheduled process at next unless it was stopped or
+// rescheduled in the meantime. monitor arms the following run once this one
+// exits.
+func (pm *ProcessManager) runScheduled(name string, p *ManagedProcess, gen uint64, next time.Time) {
+	select {
+	case <-pm.ctx.Done():
+		return
+	case <-time.After(time.Until(next)):
+	}
+
+	if !p.BeginRun(gen) {
+		return
+	}
+	pm.emitEvent(name, StatusScheduled, StatusStarting, "")
+
+	// StartRetry checks gen again, since a stop may arrive while waiting for
+	// a start slot.
+	err := pm.launch(name, p, func() (Run, error) {
+		return p.StartRetry(gen)
+	})
+	if err != nil {
+		slog.Error("scheduled run failed to start", "process", name, "error", err)
+		// No monitor runs for a failed start, so arm the next run here.
+		if err := pm.armSchedule(name, p, StatusFailed); err != nil {
+			slog.Error("failed to reschedule", "process", name, "error", err)
+		}
+	}
+}
@@ -1,35 +1,56 @@
 package process
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Status string
 
 const (
-	StatusStopped  Status = "stopped"
-	StatusStarting Status = "starting"
-	StatusRunning  Status = "running"
-	StatusFailed   Status = "failed"
-	StatusRetrying Status = "retrying"
-	StatusStopping Status = "stopping"
+	StatusStopped Status = "stopped"
+	// StatusCompleted means the process exited successfully on its own,
+	// rather than being stopped.
+	StatusCompleted Status = "completed"
+	StatusStarting  Status = "starting"
+	StatusRunning   Status = "running"
+	StatusHealthy   Status = "healthy"
+	StatusFailed    Status = "failed"
+	StatusRetrying  Status = "retrying"
+	StatusStopping  Status = "stopping"
+	// StatusScheduled means a process with a schedule is waiting for its
+	// next run.
+	StatusScheduled Status = "scheduled"
 )
 
+// IsRunning reports whether the status describes a live process, whether or
+// not its health check has passed yet.
+func (s Status) IsRunning() bool {
+	return s == StatusRunning || s == StatusHealthy
+}
+
 type ProcessState struct {
 	Name        string    `json:"name"`
 	Status      Status    `json:"status"`
 	PID         int       `json:"pid,omitempty"`
-	StartedAt   time.Time `json:"started_at,omitempty"`
-	StoppedAt   time.Time `json:"stopped_at,omitempty"`
+	PTY         bool      `json:"pty"` // output comes from a pseudo-terminal rather than pipes
+	StartedAt   time.Time `json:"started_at"`
+	StoppedAt   time.Time `json:"stopped_at"`
 	RetryCount  int       `json:"retry_count"`
-	NextRetryAt time.Time `json:"next_retry_at,omitempty"`
+	Restarts    int       `json:"restarts"`
+	NextRetryAt time.Time `json:"next_retry_at"`
+	NextRunAt   time.Time `json:"next_run_at"` // set while scheduled
 	LastError   string    `json:"last_error,omitempty"`
 	ExitCode    int       `json:"exit_code,omitempty"`
+	MemoryBytes uint64    `json:"memory_bytes,omitempty"`
+	CPUPercent  float64   `json:"cpu_percent,omitempty"`
 }
 
 func (s ProcessState) Uptime() time.Duration {
 	if s.StartedAt.IsZero() {
 		return 0
 	}
-	if s.Status == StatusRunning || s.Status == StatusStopping {
+	if s.Status.IsRunning() || s.Status == StatusStopping {
 		return time.Since(s.StartedAt)
 	}
 	if !s.StoppedAt.IsZero() {
@@ -37,3 +58,33 @@ func (s ProcessState) Uptime() time.Duration {
 	}
 	return 0
 }
+
+// MarshalJSON adds uptime_seconds, the whole seconds of Uptime, so consumers
+// don't have to derive it from the timestamps, and leaves out unset times.
+func (s ProcessState) MarshalJSON() ([]byte, error) {
+	type plain ProcessState
+	return json.Marshal(struct {
+		plain
+		StartedAt     *time.Time `json:"started_at,omitempty"`
+		StoppedAt     *time.Time `json:"stopped_at,omitempty"`
+		NextRetryAt   *time.Time `json:"next_retry_at,omitempty"`
+		NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+		UptimeSeconds int64      `json:"uptime_seconds"`
+	}{
+		plain(s),
+		optionalTime(s.StartedAt),
+		optionalTime(s.StoppedAt),
+		optionalTime(s.NextRetryAt),
+		optionalTime(s.NextRunAt),
+		int64(s.Uptime().Seconds()),
+	})
+}
+
+// optionalTime returns nil for the zero time, which omitempty can't leave
+// out on its own.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
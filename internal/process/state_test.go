@@ -0,0 +1,54 @@
+package process
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessState_MarshalJSONUptime(t *testing.T) {
+	started := time.Now().Add(-90 * time.Second)
+	data, err := json.Marshal(ProcessState{Name: "api", Status: StatusRunning, StartedAt: started, Restarts: 2})
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "api", got["name"])
+	assert.Equal(t, float64(2), got["restarts"], "regular fields are kept")
+	assert.InDelta(t, 90, got["uptime_seconds"], 1)
+
+	data, err = json.Marshal(ProcessState{Name: "db", Status: StatusStopped})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"uptime_seconds":0`)
+
+	// The field is output only; decoding ignores it.
+	var back ProcessState
+	require.NoError(t, json.Unmarshal(data, &back))
+	assert.Equal(t, "db", back.Name)
+}
+
+func TestMarshalJSON_OmitsZeroTimes(t *testing.T) {
+	data, err := json.Marshal(ProcessState{Name: "db", Status: StatusStopped})
+	require.NoError(t, err)
+	for _, field := range []string{"started_at", "stopped_at", "next_retry_at", "next_run_at"} {
+		assert.NotContains(t, string(data), field)
+	}
+
+	next := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err = json.Marshal(ProcessState{Name: "job", Status: StatusScheduled, NextRunAt: next})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"next_run_at":"2025-01-02T03:04:05Z"`)
+	var back ProcessState
+	require.NoError(t, json.Unmarshal(data, &back))
+	assert.True(t, back.NextRunAt.Equal(next))
+
+	data, err = json.Marshal(StateEvent{Name: "db", OldState: StatusRunning, NewState: StatusStopped})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "next_retry_at")
+	data, err = json.Marshal(StateEvent{Name: "db", NewState: StatusRetrying, Attempt: 1, NextRetryAt: next})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"next_retry_at":"2025-01-02T03:04:05Z"`)
+}
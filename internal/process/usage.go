@@ -0,0 +1,72 @@
+package process
+
+import "time"
+
+// usageInterval is how often the manager samples CPU and memory usage.
+const usageInterval = time.Second
+
+// usageSample is a point-in-time reading of a process group's resource usage.
+type usageSample struct {
+	cpuTicks    uint64
+	memoryBytes uint64
+}
+
+// usageReading remembers the previous sample for a process so CPU usage can be
+// computed as a rate.
+type usageReading struct {
+	pid      int
+	cpuTicks uint64
+	at       time.Time
+}
+
+// sampleUsage periodically updates CPU and memory usage for running processes
+// until the manager's context is cancelled.
+func (pm *ProcessManager) sampleUsage() {
+	prev := make(map[string]usageReading)
+	ticker := time.NewTicker(usageInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pm.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pm.mu.RLock()
+		procs := make(map[string]*ManagedProcess)
+		pids := make(map[string]int)
+		pgids := make(map[int]bool)
+		for name, p := range pm.processes {
+			if pid := p.State().PID; pid != 0 {
+				procs[name] = p
+				pids[name] = pid
+				pgids[pid] = true // processes run in their own group, so pgid == pid
+			}
+		}
+		pm.mu.RUnlock()
+
+		now := time.Now()
+		samples := readGroupUsage(pgids)
+		next := make(map[string]usageReading)
+		for name, pid := range pids {
+			sample, ok := samples[pid]
+			if !ok {
+				continue
+			}
+
+			var cpu float64
+			if last, ok := prev[name]; ok && last.pid == pid && sample.cpuTicks >= last.cpuTicks {
+				elapsed := now.Sub(last.at).Seconds()
+				if elapsed > 0 {
+					cpu = float64(sample.cpuTicks-last.cpuTicks) / clockTicks / elapsed * 100
+				}
+			}
+
+			procs[name].SetUsage(sample.memoryBytes, cpu)
+
+			next[name] = usageReading{pid: pid, cpuTicks: sample.cpuTicks, at: now}
+		}
+		prev = next
+	}
+}
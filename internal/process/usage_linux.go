@@ -0,0 +1,65 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// clockTicks is the kernel's USER_HZ, which is 100 on all mainstream Linux
+// architectures.
+const clockTicks = 100
+
+// readGroupUsage sums CPU time and resident memory for every process whose
+// process group is in pgids, keyed by process group ID. Processes that vanish
+// mid-scan are skipped.
+func readGroupUsage(pgids map[int]bool) map[int]usageSample {
+	result := make(map[int]usageSample)
+	if len(pgids) == 0 {
+		return result
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return result
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", e.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+
+		// The command name may contain spaces, so split after its closing paren.
+		stat := string(data)
+		end := strings.LastIndexByte(stat, ')')
+		if end < 0 {
+			continue
+		}
+		fields := strings.Fields(stat[end+1:])
+		// fields[0] is field 3 (state) in proc(5).
+		if len(fields) < 22 {
+			continue
+		}
+		pgrp, _ := strconv.Atoi(fields[2])
+		if !pgids[pgrp] {
+			continue
+		}
+		utime, _ := strconv.ParseUint(fields[11], 10, 64)
+		stime, _ := strconv.ParseUint(fields[12], 10, 64)
+		rss, _ := strconv.ParseUint(fields[21], 10, 64)
+
+		s := result[pgrp]
+		s.cpuTicks += utime + stime
+		s.memoryBytes += rss * pageSize
+		result[pgrp] = s
+	}
+	return result
+}
@@ -0,0 +1,24 @@
+//go:build linux && !ci
+
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGroupUsage(t *testing.T) {
+	proc, _ := newTestProcess("sleep 3600")
+	require.NoError(t, proc.Start())
+	defer proc.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	pid := proc.State().PID
+
+	samples := readGroupUsage(map[int]bool{pid: true})
+	require.Contains(t, samples, pid)
+	assert.Greater(t, samples[pid].memoryBytes, uint64(0))
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package process
+
+const clockTicks = 100
+
+// readGroupUsage is not supported on this platform; usage stays at zero.
+func readGroupUsage(pgids map[int]bool) map[int]usageSample {
+	return map[int]usageSample{}
+}
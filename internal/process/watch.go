@@ -0,0 +1,132 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// startWatching starts watching the files of a process unless a watcher is
+// already running. The watcher outlives the process's runs, so a process
+// that crashed is started again once its files are fixed; only stopping or
+// removing the process ends it.
+func (pm *ProcessManager) startWatching(name string, p *ManagedProcess) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if _, ok := pm.watchers[name]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(pm.ctx)
+	pm.watchers[name] = cancel
+	go pm.watchFiles(ctx, name, p)
+}
+
+// stopWatching stops the file watcher of a process, if it has one.
+func (pm *ProcessManager) stopWatching(name string) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if cancel, ok := pm.watchers[name]; ok {
+		cancel()
+		delete(pm.watchers, name)
+	}
+}
+
+// watchFiles restarts a process when a file under its watch paths changes,
+// or starts it if it has failed or exited. Changes are debounced so a
+// burst, such as a git checkout, causes a single restart. It runs until ctx
+// is cancelled. Restarting stops the process, which cancels ctx, so after a
+// restart the watcher started by the new run takes over.
+func (pm *ProcessManager) watchFiles(ctx context.Context, name string, p *ManagedProcess) {
+	procCfg := pm.currentConfig().Processes[name]
+	w := procCfg.Watch
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		p.log.WriteString(fmt.Sprintf("[shepherd] Cannot watch files: %s", err))
+		return
+	}
+	defer watcher.Close()
+
+	for _, path := range w.Paths {
+		if !filepath.IsAbs(path) && procCfg.WorkingDir != "" {
+			path = filepath.Join(procCfg.WorkingDir, path)
+		}
+		if err := addWatchTree(watcher, path); err != nil {
+			p.log.WriteString(fmt.Sprintf("[shepherd] Cannot watch %s: %s", path, err))
+		}
+	}
+
+	// The timer only runs once a matching change has been seen.
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+	var changed string
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if ev.Has(fsnotify.Create) {
+				// New directories aren't covered by the parent's watch.
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					addWatchTree(watcher, ev.Name)
+				}
+			}
+			if ev.Op == fsnotify.Chmod || !w.Matches(ev.Name) {
+				continue
+			}
+			changed = ev.Name
+			timer.Reset(w.DebounceDuration())
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Debug("file watch error", "process", name, "error", err)
+		case <-timer.C:
+			if status := p.State().Status; status == StatusFailed || status == StatusCompleted {
+				p.log.WriteString(fmt.Sprintf("[shepherd] Starting: %s changed", changed))
+				if err := pm.StartProcess(name); err != nil {
+					slog.Warn("failed to start on file change", "process", name, "error", err)
+				}
+				continue
+			}
+			p.log.WriteString(fmt.Sprintf("[shepherd] Restarting: %s changed", changed))
+			if err := pm.RestartProcess(name); err != nil {
+				slog.Warn("failed to restart on file change", "process", name, "error", err)
+			}
+		}
+	}
+}
+
+// addWatchTree watches path and, if it is a directory, every subdirectory
+// below it except hidden ones such as .git.
+func addWatchTree(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return watcher.Add(path)
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
@@ -0,0 +1,59 @@
+// Package session saves the set of running processes when shepherd exits so
+// it can be restored on the next launch.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// State is what gets written to the session file.
+type State struct {
+	Running []string `json:"running"`
+}
+
+// Save writes the names of the running processes to path, replacing any
+// previous session.
+func Save(path string, running []string) error {
+	names := append([]string(nil), running...)
+	sort.Strings(names)
+	data, err := json.MarshalIndent(State{Running: names}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating session directory: %w", err)
+	}
+
+	// Write to a temp file and rename so a crash mid-write can't leave a
+	// truncated session behind.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing session: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing session: %w", err)
+	}
+	return nil
+}
+
+// Load returns the process names saved at path. A missing file is not an
+// error and yields no names.
+func Load(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading session: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing session %s: %w", path, err)
+	}
+	return s.Running, nil
+}
@@ -0,0 +1,38 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "session.json")
+
+	require.NoError(t, Save(path, []string{"web", "db"}))
+	names, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "web"}, names)
+
+	require.NoError(t, Save(path, nil))
+	names, err = Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestLoad_Missing(t *testing.T) {
+	names, err := Load(filepath.Join(t.TempDir(), "session.json"))
+	require.NoError(t, err)
+	assert.Nil(t, names)
+}
+
+func TestLoad_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+
+	_, err := Load(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package tui
+
+import "strings"
+
+const ansiReset = "\x1b[0m"
+
+// sanitizeLogLine prepares a raw PTY line for the viewport. Cursor movement,
+// OSC sequences, and stray control characters are dropped so they can't
+// corrupt the layout. SGR color sequences are kept when keepColor is true and
+// stripped otherwise. A carriage return discards what came before it, the way
+// a terminal would overwrite a progress line.
+func sanitizeLogLine(line string, keepColor bool) string {
+	if i := strings.LastIndex(strings.TrimRight(line, "\r"), "\r"); i >= 0 {
+		line = line[i+1:]
+	}
+
+	var b strings.Builder
+	colored := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == 0x1b && i+1 < len(line) && line[i+1] == '[':
+			// CSI: ESC [ params final, where final is in 0x40-0x7e.
+			j := i + 2
+			for j < len(line) && (line[j] < 0x40 || line[j] > 0x7e) {
+				j++
+			}
+			if j < len(line) && line[j] == 'm' && keepColor {
+				b.WriteString(line[i : j+1])
+				colored = true
+			}
+			i = j
+		case c == 0x1b && i+1 < len(line) && line[i+1] == ']':
+			// OSC: ESC ] ... terminated by BEL or ESC \.
+			j := i + 2
+			for j < len(line) && line[j] != 0x07 && !(line[j] == 0x1b && j+1 < len(line) && line[j+1] == '\\') {
+				j++
+			}
+			if j < len(line) && line[j] == 0x1b {
+				j++
+			}
+			i = j
+		case c == 0x1b:
+			// Two-byte escape such as ESC ( B; skip the following byte.
+			i++
+		case c == '\t':
+			b.WriteString("    ")
+		case c < 0x20 || c == 0x7f:
+			// Drop other control characters, including a trailing \r.
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	if colored {
+		// Keep colors from bleeding into the next line or the border.
+		b.WriteString(ansiReset)
+	}
+	return b.String()
+}
+
+// stripANSI returns line with all escape sequences and control characters removed.
+func stripANSI(line string) string {
+	return sanitizeLogLine(line, false)
+}
@@ -1,9 +1,15 @@
 package tui
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/frontendtony/shepherd/internal/config"
@@ -25,7 +31,8 @@ type errMsg struct{ error }
 
 // ConfigReloadMsg is sent when config is reloaded via SIGHUP.
 type ConfigReloadMsg struct {
-	Config *config.Config
+	Config  *config.Config
+	Changed []string // processes whose definition changed, from config.DiffConfigs
 }
 
 // NotifyMsg is sent to display a temporary notification in the status bar.
@@ -38,19 +45,48 @@ type Model struct {
 	manager *process.ProcessManager
 	config  *config.Config
 
+	events      <-chan process.StateEvent // this model's subscription to manager events
+	unsubscribe func()
+
 	groups      []groupView
 	items       []listItem
 	states      map[string]process.ProcessState
 	selectedIdx int
 
+	filterInput  textinput.Model
+	filtering    bool
+	filter       string
+	sortMode     sortMode
+	statusFilter statusFilter
+
 	focusedPanel   Panel
 	selectedProc   string
 	logViewport    viewport.Model
 	autoScroll     bool
+	scrollPos      map[string]int // log offsets of processes scrolled away from the tail
+	plainLogs      bool           // strip ANSI colors from log output
+	hideTimestamps bool
+	noLevelColors  bool // don't color lines by their detected log level
+	wrapLogs       bool // soft-wrap long log lines instead of truncating
+	allLogs        bool // show every process's logs interleaved instead of the selected one's
+
+	searchInput textinput.Model
+	searching   bool
+	search      string
+	matches     []int // line indices in the current log that match search
+	matchIdx    int
+
 	showHelp       bool
+	showInspector  bool
 	fullScreenLogs bool
 	confirmQuit    bool
 	confirmStopAll bool
+	confirmStopGrp string   // group awaiting confirmation to stop
+	confirmStop    string   // process awaiting confirmation to stop (confirm_stop)
+	confirmPrompt  string   // question shown for confirmStop
+	confirmCmd     stopFunc // runs once confirmStop is confirmed
+	confirmRestart []string // running processes changed by a config reload
+	confirmRestore []string // processes running when the last session ended
 	width, height  int
 
 	autoStart    string
@@ -61,17 +97,45 @@ type Model struct {
 	ready        bool
 }
 
-// NewModel creates the TUI model wired to the given process manager.
-func NewModel(mgr *process.ProcessManager, cfg *config.Config, autoStart string) Model {
+// NewModel creates the TUI model wired to the given process manager. restore
+// is the set of processes saved by the previous session; the user is asked
+// whether to start them.
+func NewModel(mgr *process.ProcessManager, cfg *config.Config, autoStart string, restore []string) Model {
+	fi := textinput.New()
+	fi.Prompt = "/"
+	fi.Placeholder = "filter"
+
+	si := textinput.New()
+	si.Prompt = "/"
+	si.Placeholder = "search logs"
+
+	events, unsubscribe := mgr.Subscribe()
 	m := Model{
+		events:       events,
+		unsubscribe:  unsubscribe,
+		filterInput:  fi,
+		searchInput:  si,
 		manager:      mgr,
 		config:       cfg,
 		autoStart:    autoStart,
 		autoScroll:   true,
+		scrollPos:    make(map[string]int),
 		states:       make(map[string]process.ProcessState),
 		focusedPanel: PanelProcessList,
 	}
 
+	// Offer to bring back the last session unless something was named on
+	// the command line.
+	if autoStart == "" {
+		for _, name := range restore {
+			if _, ok := cfg.Processes[name]; ok {
+				m.confirmRestore = append(m.confirmRestore, name)
+			}
+		}
+	}
+
+	applyKeybindings(cfg.Keybindings)
+	applyTheme(cfg.Theme)
 	m.buildGroups()
 	m.rebuildItems()
 	m.refreshStates()
@@ -128,14 +192,44 @@ func (m *Model) buildGroups() {
 
 func (m *Model) rebuildItems() {
 	m.items = nil
-	for i, g := range m.groups {
+	filter := strings.ToLower(m.filter)
+	for _, i := range m.groupOrder() {
+		g := m.groups[i]
+		// A matching group name keeps all its processes; otherwise only
+		// matching processes are kept, and the group is hidden if none match.
+		procs := g.processes
+		if filter != "" && !strings.Contains(strings.ToLower(g.name), filter) {
+			procs = nil
+			for _, p := range g.processes {
+				if strings.Contains(strings.ToLower(p), filter) {
+					procs = append(procs, p)
+				}
+			}
+			if len(procs) == 0 {
+				continue
+			}
+		}
+		if m.statusFilter != showAll {
+			var kept []string
+			for _, p := range procs {
+				if m.statusFilter.matches(m.states[p].Status) {
+					kept = append(kept, p)
+				}
+			}
+			if len(kept) == 0 {
+				continue
+			}
+			procs = kept
+		}
+		procs = m.sortProcesses(procs)
+
 		m.items = append(m.items, listItem{
 			isGroup:  true,
 			name:     g.name,
 			groupIdx: i,
 		})
 		if g.expanded {
-			for _, p := range g.processes {
+			for _, p := range procs {
 				m.items = append(m.items, listItem{
 					name:      p,
 					groupName: g.name,
@@ -146,16 +240,50 @@ func (m *Model) rebuildItems() {
 	}
 }
 
+// groupOrder returns group indices in display order. Groups keep their
+// configured order unless sorting by status, where the group holding the most
+// urgent process comes first.
+func (m Model) groupOrder() []int {
+	order := make([]int, len(m.groups))
+	for i := range order {
+		order[i] = i
+	}
+	if m.sortMode != sortByStatus {
+		return order
+	}
+	worst := func(g groupView) int {
+		rank := statusRank(process.StatusStopped)
+		for _, p := range g.processes {
+			if r := statusRank(m.states[p].Status); r < rank {
+				rank = r
+			}
+		}
+		return rank
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return worst(m.groups[order[a]]) < worst(m.groups[order[b]])
+	})
+	return order
+}
+
 func (m *Model) refreshStates() {
 	for _, s := range m.manager.GetAllStates() {
-		m.states[s.Name] = s
+		if _, ok := m.config.Processes[s.Name]; ok {
+			m.states[s.Name] = s
+		}
+	}
+	// Drop processes removed by a config reload.
+	for name := range m.states {
+		if _, ok := m.config.Processes[name]; !ok {
+			delete(m.states, name)
+		}
 	}
 }
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{
-		listenForEvents(m.manager),
+		listenForEvents(m.events),
 		tickEvery(),
 	}
 	if m.autoStart != "" {
@@ -166,9 +294,9 @@ func (m Model) Init() tea.Cmd {
 
 // Tea commands
 
-func listenForEvents(mgr *process.ProcessManager) tea.Cmd {
+func listenForEvents(events <-chan process.StateEvent) tea.Cmd {
 	return func() tea.Msg {
-		event, ok := <-mgr.Events()
+		event, ok := <-events
 		if !ok {
 			return nil
 		}
@@ -182,12 +310,18 @@ func tickEvery() tea.Cmd {
 	})
 }
 
+// startByNameCmd starts a stack, group, process, or tag and sums up the
+// result, naming any processes that failed.
 func startByNameCmd(mgr *process.ProcessManager, name string) tea.Cmd {
 	return func() tea.Msg {
-		if err := mgr.StartByName(name); err != nil {
+		res, err := mgr.StartByName(name)
+		if err != nil {
 			return errMsg{err}
 		}
-		return nil
+		if len(res.Failed) > 0 {
+			return errMsg{fmt.Errorf("%s: %s", name, res.Summary())}
+		}
+		return NotifyMsg{Text: fmt.Sprintf("%s: %s", name, res.Summary())}
 	}
 }
 
@@ -209,6 +343,34 @@ func stopProcessCmd(mgr *process.ProcessManager, name string) tea.Cmd {
 	}
 }
 
+func stopProcessOnlyCmd(mgr *process.ProcessManager, name string) tea.Cmd {
+	return func() tea.Msg {
+		if err := mgr.StopProcessOnly(name); err != nil {
+			return errMsg{err}
+		}
+		return nil
+	}
+}
+
+func killProcessCmd(mgr *process.ProcessManager, name string) tea.Cmd {
+	return func() tea.Msg {
+		if err := mgr.KillProcess(name); err != nil {
+			return errMsg{err}
+		}
+		return nil
+	}
+}
+
+// signalProcessCmd sends sig to a running process and confirms it was sent.
+func signalProcessCmd(mgr *process.ProcessManager, name string, sig syscall.Signal) tea.Cmd {
+	return func() tea.Msg {
+		if err := mgr.SignalProcess(name, sig); err != nil {
+			return errMsg{err}
+		}
+		return NotifyMsg{Text: fmt.Sprintf("Sent %s to %s", config.SignalName(sig), name)}
+	}
+}
+
 func restartProcessCmd(mgr *process.ProcessManager, name string) tea.Cmd {
 	return func() tea.Msg {
 		if err := mgr.RestartProcess(name); err != nil {
@@ -218,39 +380,61 @@ func restartProcessCmd(mgr *process.ProcessManager, name string) tea.Cmd {
 	}
 }
 
-func startGroupCmd(mgr *process.ProcessManager, processes []string) tea.Cmd {
+// startGroupCmd starts processes and sums up the result under label.
+func startGroupCmd(mgr *process.ProcessManager, label string, processes []string) tea.Cmd {
 	return func() tea.Msg {
-		for _, name := range processes {
-			if err := mgr.StartProcess(name); err != nil {
-				return errMsg{err}
-			}
+		res, err := mgr.StartProcesses(processes)
+		if err != nil {
+			return errMsg{err}
 		}
-		return nil
+		if len(res.Failed) > 0 {
+			return errMsg{fmt.Errorf("%s: %s", label, res.Summary())}
+		}
+		return NotifyMsg{Text: fmt.Sprintf("%s: %s", label, res.Summary())}
 	}
 }
 
 func stopGroupCmd(mgr *process.ProcessManager, processes []string) tea.Cmd {
 	return func() tea.Msg {
-		for _, name := range processes {
-			if err := mgr.StopProcess(name); err != nil {
-				return errMsg{err}
-			}
+		if err := mgr.StopProcesses(processes); err != nil {
+			return errMsg{err}
 		}
 		return nil
 	}
 }
 
-func startAllCmd(mgr *process.ProcessManager, cfg *config.Config) tea.Cmd {
+func restartGroupCmd(mgr *process.ProcessManager, processes []string) tea.Cmd {
 	return func() tea.Msg {
-		for name := range cfg.Processes {
-			if err := mgr.StartProcess(name); err != nil {
-				return errMsg{err}
-			}
+		if err := mgr.RestartProcesses(processes); err != nil {
+			return errMsg{err}
 		}
 		return nil
 	}
 }
 
+func restartChangedCmd(mgr *process.ProcessManager, names []string) tea.Cmd {
+	return func() tea.Msg {
+		if err := mgr.RestartProcesses(names); err != nil {
+			return errMsg{err}
+		}
+		return NotifyMsg{Text: fmt.Sprintf("Restarted %d changed process(es)", len(names))}
+	}
+}
+
+// startAllCmd starts every process and sums up the result.
+func startAllCmd(mgr *process.ProcessManager) tea.Cmd {
+	return func() tea.Msg {
+		res, err := mgr.StartAll()
+		if err != nil {
+			return errMsg{err}
+		}
+		if len(res.Failed) > 0 {
+			return errMsg{fmt.Errorf("start all: %s", res.Summary())}
+		}
+		return NotifyMsg{Text: "start all: " + res.Summary()}
+	}
+}
+
 func stopAllCmd(mgr *process.ProcessManager) tea.Cmd {
 	return func() tea.Msg {
 		if err := mgr.StopAll(); err != nil {
@@ -259,3 +443,30 @@ func stopAllCmd(mgr *process.ProcessManager) tea.Cmd {
 		return nil
 	}
 }
+
+// exportLogsCmd writes the named process's log buffer, stripped of ANSI
+// escapes, to ~/shepherd-<name>-<timestamp>.log.
+func exportLogsCmd(mgr *process.ProcessManager, name string, withTime bool) tea.Cmd {
+	return func() tea.Msg {
+		buf := mgr.GetLogBuffer(name)
+		if buf == nil {
+			return errMsg{fmt.Errorf("unknown process: %s", name)}
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return errMsg{fmt.Errorf("exporting logs: %w", err)}
+		}
+
+		var b strings.Builder
+		for _, e := range buf.Entries(0) {
+			b.WriteString(stripANSI(e.Format(withTime)))
+			b.WriteByte('\n')
+		}
+
+		path := filepath.Join(home, fmt.Sprintf("shepherd-%s-%s.log", name, time.Now().Format("20060102-150405")))
+		if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+			return errMsg{fmt.Errorf("exporting logs: %w", err)}
+		}
+		return NotifyMsg{Text: "Logs saved to " + path}
+	}
+}
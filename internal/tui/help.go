@@ -1,8 +1,10 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -12,6 +14,14 @@ func (m Model) renderHelp() string {
 		Foreground(colorAccent).
 		Render("Shepherd - Keybindings")
 
+	// Keys come from the keymap so rebindings show up here.
+	line := func(bound, desc string) string {
+		return fmt.Sprintf("%-7s %s", bound, desc)
+	}
+	k := func(b key.Binding) string {
+		return b.Help().Key
+	}
+
 	sections := []struct {
 		header   string
 		bindings []string
@@ -19,36 +29,66 @@ func (m Model) renderHelp() string {
 		{
 			header: "Navigation",
 			bindings: []string{
-				"↑/k     Move up",
-				"↓/j     Move down",
-				"Enter   Expand/collapse group",
-				"Tab     Switch panel focus",
-				"l       Focus log panel",
-				"f       Fullscreen logs",
+				line(k(keys.Up), "Move up"),
+				line(k(keys.Down), "Move down"),
+				line(k(keys.Enter), "Expand/collapse group, inspect process"),
+				line(k(keys.Inspect), "Inspect selected process"),
+				line(k(keys.Tab), "Switch panel focus"),
+				line(k(keys.Logs), "Focus log panel"),
+				line(k(keys.FullScreen), "Fullscreen logs"),
+				line(k(keys.AllLogs), "Toggle logs from all processes"),
+				line(k(keys.Filter), "Filter processes (Esc clears)"),
+				line(k(keys.Sort), "Cycle sort: name, status, uptime"),
+				line(k(keys.ShowStatus), "Cycle filter: all, running, failed"),
+			},
+		},
+		{
+			header: "Log Search",
+			bindings: []string{
+				line(k(keys.Filter), "Search logs (in log panel)"),
+				line(k(keys.NextMatch)+"/"+k(keys.PrevMatch), "Next/previous match"),
+				line("Esc", "Clear search"),
+			},
+		},
+		{
+			header: "Log View",
+			bindings: []string{
+				line(k(keys.Top)+"/"+k(keys.Bottom), fmt.Sprintf("Jump to top/bottom (%s resumes following)", k(keys.Bottom))),
+				line(k(keys.Follow), "Toggle follow mode"),
+				line(k(keys.Colors), "Toggle ANSI colors"),
+				line(k(keys.Levels), "Toggle coloring by log level"),
+				line(k(keys.Timestamps), "Toggle timestamps"),
+				line(k(keys.Wrap), "Toggle line wrap"),
+				line(k(keys.Export), "Save logs to ~/shepherd-<name>-<time>.log"),
+				line(k(keys.ClearLogs), "Clear logs"),
 			},
 		},
 		{
 			header: "Process Control",
 			bindings: []string{
-				"s       Start selected process",
-				"x       Stop selected process",
-				"r       Restart selected process",
+				line(k(keys.Start), "Start selected process"),
+				line(k(keys.Stop), "Stop selected process"),
+				line(k(keys.StopOnly), "Stop without stopping dependents"),
+				line(k(keys.Kill), "Force kill (SIGKILL) selected process"),
+				line(k(keys.Restart), "Restart selected process"),
+				line(k(keys.SigUsr1)+" / "+k(keys.SigUsr2), "Send SIGUSR1 / SIGUSR2 to selected process"),
 			},
 		},
 		{
 			header: "Group/All Control",
 			bindings: []string{
-				"g       Start all in group",
-				"G       Stop all in group",
-				"a       Start all processes",
-				"X       Stop all processes",
+				line(k(keys.StartGrp), "Start all in group"),
+				line(k(keys.StopGrp), "Stop all in group"),
+				line(k(keys.RestartGrp), "Restart all in group"),
+				line(k(keys.StartAll), "Start all processes"),
+				line(k(keys.StopAll), "Stop all processes"),
 			},
 		},
 		{
 			header: "Other",
 			bindings: []string{
-				"?       Toggle this help",
-				"q       Quit",
+				line(k(keys.Help), "Toggle this help"),
+				line(k(keys.Quit), "Quit"),
 			},
 		},
 	}
@@ -65,7 +105,7 @@ func (m Model) renderHelp() string {
 		parts = append(parts, "")
 	}
 
-	parts = append(parts, lipgloss.NewStyle().Foreground(colorDim).Render("Press ? or Esc to close"))
+	parts = append(parts, lipgloss.NewStyle().Foreground(colorDim).Render("Press "+shortKey(keys.Help)+" or Esc to close"))
 
 	content := strings.Join(parts, "\n")
 
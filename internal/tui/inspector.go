@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m Model) renderInspector() string {
+	name := m.selectedProc
+	state := m.states[name]
+	cfg := m.config.Processes[name]
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent).
+		Render("Process: " + name)
+
+	labelStyle := lipgloss.NewStyle().Foreground(colorDim).Width(13)
+	row := func(label, value string) string {
+		if value == "" {
+			value = "-"
+		}
+		return labelStyle.Render(label) + value
+	}
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+
+	pid := ""
+	if state.PID != 0 {
+		pid = fmt.Sprintf("%d", state.PID)
+	}
+
+	memory, cpu, output := "", "", ""
+	if state.PID != 0 {
+		memory = formatBytes(state.MemoryBytes)
+		cpu = fmt.Sprintf("%.1f%%", state.CPUPercent)
+		output = "pipes (no PTY)"
+		if state.PTY {
+			output = "PTY"
+		}
+	}
+
+	var parts []string
+	parts = append(parts, title, "")
+	parts = append(parts,
+		row("Status", statusStyle(state.Status).Render(string(state.Status))),
+		row("PID", pid),
+		row("Memory", memory),
+		row("CPU", cpu),
+		row("Output", output),
+		row("Command", cfg.CommandLine()),
+		row("Working dir", cfg.WorkingDir),
+		row("Depends on", strings.Join(cfg.DependencyNames(), ", ")),
+		row("Started", formatTime(state.StartedAt)),
+		row("Stopped", formatTime(state.StoppedAt)),
+		row("Exit code", fmt.Sprintf("%d", state.ExitCode)),
+		row("Retry count", fmt.Sprintf("%d", state.RetryCount)),
+		row("Next retry", formatTime(state.NextRetryAt)),
+		row("Schedule", cfg.Schedule),
+		row("Next run", formatTime(state.NextRunAt)),
+		row("Last error", state.LastError),
+	)
+
+	if len(cfg.Env) > 0 {
+		keys := make([]string, 0, len(cfg.Env))
+		for k := range cfg.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		parts = append(parts, "", lipgloss.NewStyle().Bold(true).Render("Environment"))
+		for _, k := range keys {
+			parts = append(parts, fmt.Sprintf("  %s=%s", k, cfg.Env[k]))
+		}
+	}
+
+	parts = append(parts, "", lipgloss.NewStyle().Foreground(colorDim).Render("Press "+shortKey(keys.Inspect)+" or Esc to close"))
+
+	content := strings.Join(parts, "\n")
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(colorAccent).
+			Padding(1, 3).
+			MaxWidth(m.width).
+			Render(content),
+	)
+}
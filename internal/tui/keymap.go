@@ -1,6 +1,11 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/frontendtony/shepherd/internal/config"
+)
 
 type keyMap struct {
 	Up         key.Binding
@@ -8,32 +13,200 @@ type keyMap struct {
 	Enter      key.Binding
 	Start      key.Binding
 	Stop       key.Binding
+	StopOnly   key.Binding
+	Kill       key.Binding
 	Restart    key.Binding
+	SigUsr1    key.Binding
+	SigUsr2    key.Binding
 	StartGrp   key.Binding
 	StopGrp    key.Binding
+	RestartGrp key.Binding
 	StartAll   key.Binding
 	StopAll    key.Binding
 	Tab        key.Binding
 	Logs       key.Binding
 	FullScreen key.Binding
+	AllLogs    key.Binding
+	Filter     key.Binding
+	Sort       key.Binding
+	ShowStatus key.Binding
+	Inspect    key.Binding
+	NextMatch  key.Binding
+	PrevMatch  key.Binding
+	Colors     key.Binding
+	Levels     key.Binding
+	Timestamps key.Binding
+	Wrap       key.Binding
+	Export     key.Binding
+	ClearLogs  key.Binding
+	Follow     key.Binding
+	Top        key.Binding
+	Bottom     key.Binding
 	Help       key.Binding
 	Quit       key.Binding
 }
 
-var keys = keyMap{
-	Up:         key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
-	Down:       key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
-	Enter:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "expand/collapse")),
-	Start:      key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "start")),
-	Stop:       key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "stop")),
-	Restart:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "restart")),
-	StartGrp:   key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "start group")),
-	StopGrp:    key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "stop group")),
-	StartAll:   key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "start all")),
-	StopAll:    key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "stop all")),
-	Tab:        key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch panel")),
-	Logs:       key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "view logs")),
-	FullScreen: key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "fullscreen logs")),
-	Help:       key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
-	Quit:       key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+var keys = defaultKeyMap()
+
+func defaultKeyMap() keyMap {
+	bind := func(action, help, desc string) key.Binding {
+		return key.NewBinding(key.WithKeys(config.DefaultKeys[action]...), key.WithHelp(help, desc))
+	}
+	return keyMap{
+		Up:         bind("up", "↑/k", "up"),
+		Down:       bind("down", "↓/j", "down"),
+		Enter:      bind("enter", "enter", "expand/collapse"),
+		Start:      bind("start", "s", "start"),
+		Stop:       bind("stop", "x", "stop"),
+		StopOnly:   bind("stop_only", "alt+x", "stop without dependents"),
+		Kill:       bind("kill", "K", "force kill"),
+		Restart:    bind("restart", "r", "restart"),
+		SigUsr1:    bind("signal_usr1", "1", "send SIGUSR1"),
+		SigUsr2:    bind("signal_usr2", "2", "send SIGUSR2"),
+		StartGrp:   bind("start_group", "g", "start group"),
+		StopGrp:    bind("stop_group", "G", "stop group"),
+		RestartGrp: bind("restart_group", "R", "restart group"),
+		StartAll:   bind("start_all", "a", "start all"),
+		StopAll:    bind("stop_all", "X", "stop all"),
+		Tab:        bind("tab", "tab", "switch panel"),
+		Logs:       bind("logs", "l", "view logs"),
+		FullScreen: bind("fullscreen", "f", "fullscreen logs"),
+		AllLogs:    bind("all_logs", "L", "all logs"),
+		Filter:     bind("filter", "/", "filter/search"),
+		Sort:       bind("sort", "o", "cycle sort order"),
+		ShowStatus: bind("show_status", "v", "cycle status filter"),
+		Inspect:    bind("inspect", "i", "inspect"),
+		NextMatch:  bind("next_match", "n", "next match"),
+		PrevMatch:  bind("prev_match", "N", "previous match"),
+		Colors:     bind("colors", "c", "toggle log colors"),
+		Levels:     bind("levels", "e", "toggle level colors"),
+		Timestamps: bind("timestamps", "t", "toggle timestamps"),
+		Wrap:       bind("wrap", "W", "toggle line wrap"),
+		Export:     bind("export", "w", "save logs to file"),
+		ClearLogs:  bind("clear_logs", "C", "clear logs"),
+		Follow:     bind("follow", "F", "toggle follow"),
+		Top:        bind("top", "g", "top of logs"),
+		Bottom:     bind("bottom", "G", "bottom of logs"),
+		Help:       bind("help", "?", "help"),
+		Quit:       bind("quit", "q", "quit"),
+	}
+}
+
+// shortKey returns the first key bound to b, for compact hints. Arrow keys
+// are shown as arrows.
+func shortKey(b key.Binding) string {
+	bound := b.Keys()
+	if len(bound) == 0 {
+		return ""
+	}
+	switch bound[0] {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case " ":
+		return "space"
+	}
+	return bound[0]
+}
+
+// binding returns the binding for a config.KeyActions name, or nil.
+func (k *keyMap) binding(action string) *key.Binding {
+	switch action {
+	case "up":
+		return &k.Up
+	case "down":
+		return &k.Down
+	case "enter":
+		return &k.Enter
+	case "start":
+		return &k.Start
+	case "stop":
+		return &k.Stop
+	case "stop_only":
+		return &k.StopOnly
+	case "kill":
+		return &k.Kill
+	case "restart":
+		return &k.Restart
+	case "signal_usr1":
+		return &k.SigUsr1
+	case "signal_usr2":
+		return &k.SigUsr2
+	case "start_group":
+		return &k.StartGrp
+	case "stop_group":
+		return &k.StopGrp
+	case "restart_group":
+		return &k.RestartGrp
+	case "start_all":
+		return &k.StartAll
+	case "stop_all":
+		return &k.StopAll
+	case "tab":
+		return &k.Tab
+	case "logs":
+		return &k.Logs
+	case "fullscreen":
+		return &k.FullScreen
+	case "all_logs":
+		return &k.AllLogs
+	case "filter":
+		return &k.Filter
+	case "sort":
+		return &k.Sort
+	case "show_status":
+		return &k.ShowStatus
+	case "inspect":
+		return &k.Inspect
+	case "next_match":
+		return &k.NextMatch
+	case "prev_match":
+		return &k.PrevMatch
+	case "colors":
+		return &k.Colors
+	case "levels":
+		return &k.Levels
+	case "timestamps":
+		return &k.Timestamps
+	case "wrap":
+		return &k.Wrap
+	case "export":
+		return &k.Export
+	case "clear_logs":
+		return &k.ClearLogs
+	case "follow":
+		return &k.Follow
+	case "top":
+		return &k.Top
+	case "bottom":
+		return &k.Bottom
+	case "help":
+		return &k.Help
+	case "quit":
+		return &k.Quit
+	}
+	return nil
+}
+
+// applyKeybindings resets keys to the defaults and replaces the keys of each
+// action named in overrides. Overrides are assumed to have passed
+// config.Validate.
+func applyKeybindings(overrides map[string]config.KeyList) {
+	keys = defaultKeyMap()
+	for action, list := range overrides {
+		b := keys.binding(action)
+		if b == nil || len(list) == 0 {
+			continue
+		}
+		bound := make([]string, len(list))
+		for i, k := range list {
+			if k == "space" {
+				k = " " // Bubble Tea reports the space bar as " "
+			}
+			bound[i] = k
+		}
+		b.SetKeys(bound...)
+		b.SetHelp(strings.Join(list, "/"), b.Help().Desc)
+	}
 }
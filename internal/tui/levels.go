@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// levelPattern finds a log level in a line: an upper-case level word such as
+// ERROR or WARN, or a structured level=error / "level":"warn" field. Lower-case
+// words on their own are too common in ordinary output to count.
+var levelPattern = regexp.MustCompile(
+	`\b(FATAL|PANIC|CRITICAL|ERROR|ERR|WARNING|WARN|INFO|DEBUG)\b` +
+		`|(?i:\blevel"?\s*[=:]\s*"?(fatal|panic|critical|error|err|warning|warn|info|debug)\b)`)
+
+// detectLevel returns the first log level named in line, lower-cased, or "".
+func detectLevel(line string) string {
+	m := levelPattern.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	level := m[1]
+	if level == "" {
+		level = m[2]
+	}
+	return strings.ToLower(level)
+}
+
+// colorByLevel colors a sanitized log line by its detected level. Lines that
+// carry their own colors are left alone.
+func colorByLevel(line string) string {
+	if strings.Contains(line, "\x1b[") {
+		return line
+	}
+	var color lipgloss.TerminalColor
+	switch detectLevel(line) {
+	case "fatal", "panic", "critical", "error", "err":
+		color = colorFailed
+	case "warning", "warn":
+		color = colorRetrying
+	case "info":
+		color = colorStarting
+	case "debug":
+		color = colorDim
+	default:
+		return line
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(line)
+}
@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct{ line, want string }{
+		{"[12:00:00] ERROR connection refused", "error"},
+		{"2024/01/02 WARN: disk 91% full", "warn"},
+		{`time=... level=info msg="listening"`, "info"},
+		{`{"level":"debug","msg":"tick"}`, "debug"},
+		{"FATAL: out of memory", "fatal"},
+		{"no error here, just words", ""},
+		{"INFORMATION overload", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, detectLevel(tt.line), tt.line)
+	}
+}
+
+func TestColorByLevel_LeavesColoredLines(t *testing.T) {
+	line := "\x1b[31mERROR\x1b[0m boom"
+	assert.Equal(t, line, colorByLevel(line))
+	assert.Equal(t, "plain line", colorByLevel("plain line"))
+}
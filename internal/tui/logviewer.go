@@ -1,9 +1,14 @@
 package tui
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/frontendtony/shepherd/internal/logging"
+	"github.com/muesli/reflow/wrap"
 )
 
 func (m Model) renderLogPanel(width, height int) string {
@@ -18,7 +23,7 @@ func (m Model) renderLogPanel(width, height int) string {
 	contentHeight := height - 2
 
 	var content string
-	if m.selectedProc == "" || !m.ready {
+	if (m.selectedProc == "" && !m.allLogs) || !m.ready {
 		content = lipgloss.NewStyle().
 			Foreground(colorDim).
 			Render("Select a process to view logs")
@@ -26,11 +31,18 @@ func (m Model) renderLogPanel(width, height int) string {
 		content = m.logViewport.View()
 	}
 
-	// Show scroll indicator when not at bottom
-	if m.ready && focused && m.selectedProc != "" && !m.logViewport.AtBottom() {
+	// The search line takes precedence over the scroll indicator.
+	if m.ready && (m.searching || m.search != "") {
+		lines := strings.Split(content, "\n")
+		if len(lines) > 0 {
+			lines[len(lines)-1] = m.renderSearchLine()
+			content = strings.Join(lines, "\n")
+		}
+	} else if m.ready && focused && (m.selectedProc != "" || m.allLogs) && (!m.autoScroll || !m.logViewport.AtBottom()) {
+		// Show scroll indicator when not following the tail.
 		indicator := lipgloss.NewStyle().
 			Foreground(colorAccent).
-			Render("  ↓ new output below")
+			Render("  ↓ new output below (" + shortKey(keys.Bottom) + " to follow)")
 		lines := strings.Split(content, "\n")
 		if len(lines) > 0 {
 			lines[len(lines)-1] = indicator
@@ -47,23 +59,169 @@ func (m Model) renderLogPanel(width, height int) string {
 }
 
 func (m *Model) updateLogContent() {
-	if m.selectedProc == "" || !m.ready {
+	if !m.ready || (m.selectedProc == "" && !m.allLogs) {
 		return
 	}
-	buf := m.manager.GetLogBuffer(m.selectedProc)
-	if buf == nil {
-		m.logViewport.SetContent("No logs available")
-		return
+	var lines []string
+	if m.allLogs {
+		lines = m.allLogLines()
+	} else {
+		buf := m.manager.GetLogBuffer(m.selectedProc)
+		if buf == nil {
+			m.logViewport.SetContent("No logs available")
+			return
+		}
+		entries := buf.Entries(0)
+		lines = make([]string, len(entries))
+		for i, e := range entries {
+			lines[i] = m.renderLogLine(e)
+		}
 	}
-	lines := buf.All()
 	if len(lines) == 0 {
 		m.logViewport.SetContent(
 			lipgloss.NewStyle().Foreground(colorDim).Render("No output yet"),
 		)
 		return
 	}
+	lines = m.highlightMatches(lines)
+	if m.wrapLogs {
+		lines = m.wrapLines(lines)
+	}
 	m.logViewport.SetContent(strings.Join(lines, "\n"))
 	if m.autoScroll {
 		m.logViewport.GotoBottom()
 	}
 }
+
+// allLogLines interleaves the logs of every process by timestamp, like
+// docker-compose, prefixing each line with its source in a color of its own.
+// Lines written without a timestamp keep their place after the line before
+// them in the same buffer.
+func (m *Model) allLogLines() []string {
+	names := make([]string, 0, len(m.config.Processes))
+	width := 0
+	for name := range m.config.Processes {
+		names = append(names, name)
+		width = max(width, len(name))
+	}
+	sort.Strings(names)
+
+	type mergedEntry struct {
+		at     time.Time
+		prefix string
+		entry  logging.Entry
+	}
+	var merged []mergedEntry
+	for i, name := range names {
+		buf := m.manager.GetLogBuffer(name)
+		if buf == nil {
+			continue
+		}
+		style := lipgloss.NewStyle().Foreground(tagColors[i%len(tagColors)])
+		prefixes := make(map[string]string)
+		var last time.Time
+		for _, e := range buf.Entries(0) {
+			if !e.Time.IsZero() {
+				last = e.Time
+			}
+			tag := e.Tag
+			if tag == "" {
+				tag = name
+			}
+			prefix, ok := prefixes[tag]
+			if !ok {
+				prefix = style.Render(fmt.Sprintf("%-*s |", width, tag)) + " "
+				prefixes[tag] = prefix
+			}
+			merged = append(merged, mergedEntry{at: last, prefix: prefix, entry: e})
+		}
+	}
+	sort.SliceStable(merged, func(a, b int) bool {
+		return merged[a].at.Before(merged[b].at)
+	})
+
+	lines := make([]string, len(merged))
+	for i, me := range merged {
+		lines[i] = me.prefix + m.renderLogLine(me.entry)
+	}
+	return lines
+}
+
+// renderLogLine sanitizes an entry for display, honoring the timestamp,
+// color, and level color toggles.
+func (m *Model) renderLogLine(e logging.Entry) string {
+	line := sanitizeLogLine(e.Format(!m.hideTimestamps), !m.plainLogs)
+	if !m.noLevelColors {
+		line = colorByLevel(line)
+	}
+	return line
+}
+
+// highlightMatches records which lines contain the search term and returns the
+// lines with matches highlighted.
+func (m *Model) highlightMatches(lines []string) []string {
+	m.matches = nil
+	if m.search == "" {
+		return lines
+	}
+
+	needle := strings.ToLower(m.search)
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		plain := stripANSI(l)
+		if strings.Contains(strings.ToLower(plain), needle) {
+			m.matches = append(m.matches, i)
+			out[i] = searchMatchStyle.Render(plain)
+		} else {
+			out[i] = l
+		}
+	}
+	if m.matchIdx >= len(m.matches) {
+		m.matchIdx = 0
+	}
+	return out
+}
+
+// wrapLines breaks lines to fit the viewport width and moves the recorded
+// search matches to the first row of each matching line.
+func (m *Model) wrapLines(lines []string) []string {
+	width := m.logViewport.Width
+	if width <= 0 {
+		return lines
+	}
+	starts := make([]int, len(lines))
+	out := make([]string, 0, len(lines))
+	for i, l := range lines {
+		starts[i] = len(out)
+		out = append(out, strings.Split(wrap.String(l, width), "\n")...)
+	}
+	for i, line := range m.matches {
+		m.matches[i] = starts[line]
+	}
+	return out
+}
+
+// jumpToMatch scrolls the log viewport to the next (delta > 0) or previous
+// (delta < 0) search match, wrapping around at either end.
+func (m *Model) jumpToMatch(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.matchIdx = (m.matchIdx + delta + len(m.matches)) % len(m.matches)
+	m.autoScroll = false
+	m.logViewport.SetYOffset(m.matches[m.matchIdx])
+}
+
+// renderSearchLine renders the search prompt and match position.
+func (m Model) renderSearchLine() string {
+	line := m.searchInput.View()
+	if m.search != "" {
+		if len(m.matches) == 0 {
+			line += lipgloss.NewStyle().Foreground(colorDim).Render("  no matches")
+		} else {
+			line += lipgloss.NewStyle().Foreground(colorDim).
+				Render(fmt.Sprintf("  %d/%d", m.matchIdx+1, len(m.matches)))
+		}
+	}
+	return line
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -21,12 +22,131 @@ type listItem struct {
 	groupIdx  int
 }
 
+// sortMode orders processes within each group.
+type sortMode int
+
+const (
+	sortByName sortMode = iota
+	sortByStatus
+	sortByUptime
+)
+
+func (s sortMode) String() string {
+	switch s {
+	case sortByStatus:
+		return "status"
+	case sortByUptime:
+		return "uptime"
+	default:
+		return "name"
+	}
+}
+
+// statusFilter limits the process list to processes in certain states.
+type statusFilter int
+
+const (
+	showAll statusFilter = iota
+	showRunning
+	showFailed
+)
+
+func (f statusFilter) String() string {
+	switch f {
+	case showRunning:
+		return "running"
+	case showFailed:
+		return "failed"
+	default:
+		return "all"
+	}
+}
+
+func (f statusFilter) matches(status process.Status) bool {
+	switch f {
+	case showRunning:
+		return status.IsRunning()
+	case showFailed:
+		return status == process.StatusFailed || status == process.StatusRetrying
+	default:
+		return true
+	}
+}
+
+// statusRank orders statuses so that problems sort first.
+func statusRank(status process.Status) int {
+	switch status {
+	case process.StatusFailed:
+		return 0
+	case process.StatusRetrying:
+		return 1
+	case process.StatusStarting, process.StatusStopping:
+		return 2
+	case process.StatusRunning:
+		return 3
+	case process.StatusHealthy:
+		return 4
+	case process.StatusScheduled:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// sortProcesses returns names ordered by the current sort mode. Ties are
+// broken by name so the order is stable across refreshes.
+func (m Model) sortProcesses(names []string) []string {
+	sorted := append([]string(nil), names...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := m.states[sorted[i]], m.states[sorted[j]]
+		switch m.sortMode {
+		case sortByStatus:
+			if ra, rb := statusRank(a.Status), statusRank(b.Status); ra != rb {
+				return ra < rb
+			}
+		case sortByUptime:
+			// Running processes first, longest-running at the top.
+			if a.Status.IsRunning() != b.Status.IsRunning() {
+				return a.Status.IsRunning()
+			}
+			if a.Status.IsRunning() && a.StartedAt != b.StartedAt {
+				return a.StartedAt.Before(b.StartedAt)
+			}
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// renderListModeLine describes a non-default sort order or status filter.
+func (m Model) renderListModeLine() string {
+	var parts []string
+	if m.sortMode != sortByName {
+		parts = append(parts, "sort: "+m.sortMode.String())
+	}
+	if m.statusFilter != showAll {
+		count := m.countRunning()
+		if m.statusFilter == showFailed {
+			count = m.countByStatus(process.StatusFailed) + m.countByStatus(process.StatusRetrying)
+		}
+		parts = append(parts, fmt.Sprintf("show: %s (%d)", m.statusFilter, count))
+	}
+	return lipgloss.NewStyle().Foreground(colorDim).Render(" " + strings.Join(parts, " · "))
+}
+
 func (m Model) renderProcessList(width, height int) string {
 	focused := m.focusedPanel == PanelProcessList
 	innerWidth := width - 2 // border
 
 	var lines []string
 
+	if m.filtering || m.filter != "" {
+		lines = append(lines, " "+m.filterInput.View())
+	}
+	if m.sortMode != sortByName || m.statusFilter != showAll {
+		lines = append(lines, m.renderListModeLine())
+	}
+
 	for i, item := range m.items {
 		var line string
 
@@ -80,7 +200,7 @@ func (m Model) renderGroupRow(item listItem, width int) string {
 
 	running := 0
 	for _, p := range g.processes {
-		if s, ok := m.states[p]; ok && s.Status == process.StatusRunning {
+		if s, ok := m.states[p]; ok && s.Status.IsRunning() {
 			running++
 		}
 	}
@@ -95,10 +215,17 @@ func (m Model) renderProcessRow(item listItem, width int) string {
 	styledIcon := statusStyle(state.Status).Render(icon)
 
 	info := string(state.Status)
-	if state.Status == process.StatusRunning {
+	if state.Status.IsRunning() {
 		info = formatUptime(state.Uptime())
+		if state.MemoryBytes > 0 {
+			info = formatBytes(state.MemoryBytes) + " " + info
+		}
 	} else if state.Status == process.StatusRetrying {
-		info = fmt.Sprintf("retry #%d", state.RetryCount)
+		info = formatRetry(state)
+	} else if state.Status == process.StatusScheduled {
+		info = formatNextRun(state)
+	} else if state.Status == process.StatusFailed {
+		info = formatFailed(state)
 	}
 
 	styledInfo := statusStyle(state.Status).Render(info)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/muesli/reflow/truncate"
 )
 
 func (m Model) renderStatusBar() string {
@@ -14,41 +15,74 @@ func (m Model) renderStatusBar() string {
 		Foreground(lipgloss.Color("#FFFFFF"))
 
 	if m.confirmQuit {
-		running := m.countByStatus(process.StatusRunning)
+		running := m.countRunning()
 		return style.Width(m.width).Render(fmt.Sprintf(" %d process(es) running. Quit? (y/n)", running))
 	}
 	if m.confirmStopAll {
-		running := m.countByStatus(process.StatusRunning)
+		running := m.countRunning()
 		return style.Width(m.width).Render(fmt.Sprintf(" Stop all %d process(es)? (y/n)", running))
 	}
+	if m.confirmStopGrp != "" {
+		var running int
+		if g := m.groupByName(m.confirmStopGrp); g != nil {
+			running = m.countRunningIn(g.processes)
+		}
+		return style.Width(m.width).Render(fmt.Sprintf(" Stop %d running process(es) in %s? (y/n)", running, m.confirmStopGrp))
+	}
+	if m.confirmStop != "" {
+		return style.Width(m.width).Render(fmt.Sprintf(" %s (y/n)", m.confirmPrompt))
+	}
+	if m.confirmRestore != nil {
+		return style.Width(m.width).Render(fmt.Sprintf(" Restore last session (%s)? (y/n)", strings.Join(m.confirmRestore, ", ")))
+	}
+	if m.confirmRestart != nil {
+		return style.Width(m.width).Render(fmt.Sprintf(" Config changed for %s. Restart? (y/n)", strings.Join(m.confirmRestart, ", ")))
+	}
 
 	if m.err != nil {
 		return style.Copy().
-			Background(lipgloss.Color("#E74C3C")).
+			Background(colorFailed).
 			Width(m.width).
 			Render(fmt.Sprintf(" Error: %s", m.err.Error()))
 	}
 
 	if m.notification != "" {
 		return style.Copy().
-			Background(lipgloss.Color("#2ECC71")).
+			Background(colorRunning).
 			Foreground(lipgloss.Color("#000000")).
 			Width(m.width).
 			Render(fmt.Sprintf(" %s", m.notification))
 	}
 
-	running := m.countByStatus(process.StatusRunning)
+	running := m.countRunning()
 	total := len(m.states)
 	left := fmt.Sprintf(" %d/%d running", running, total)
+	// Say why the selected process failed without opening the inspector.
+	if s := m.states[m.selectedProc]; s.Status == process.StatusFailed && s.LastError != "" && m.focusedPanel == PanelProcessList {
+		left += fmt.Sprintf(" · %s: %s", m.selectedProc, s.LastError)
+	}
 
 	var hints []string
 	if m.focusedPanel == PanelProcessList {
-		hints = append(hints, "↑/↓ navigate", "s start", "x stop", "r restart", "f logs", "? help")
+		hints = append(hints,
+			shortKey(keys.Up)+"/"+shortKey(keys.Down)+" navigate",
+			shortKey(keys.Start)+" start",
+			shortKey(keys.Stop)+" stop",
+			shortKey(keys.Restart)+" restart",
+			shortKey(keys.FullScreen)+" logs",
+			shortKey(keys.Filter)+" filter",
+			shortKey(keys.Help)+" help")
 	} else {
-		hints = append(hints, "↑/↓ scroll", "f fullscreen", "tab back", "? help")
+		hints = append(hints, "↑/↓ scroll",
+			shortKey(keys.FullScreen)+" fullscreen",
+			shortKey(keys.Tab)+" back",
+			shortKey(keys.Help)+" help")
 	}
 	right := strings.Join(hints, "  ") + " "
 
+	if avail := m.width - lipgloss.Width(right) - 1; lipgloss.Width(left) > avail && avail > 0 {
+		left = truncate.StringWithTail(left, uint(avail), "…")
+	}
 	padding := m.width - lipgloss.Width(left) - lipgloss.Width(right)
 	if padding < 1 {
 		padding = 1
@@ -66,3 +100,41 @@ func (m Model) countByStatus(status process.Status) int {
 	}
 	return count
 }
+
+// countRunningIn counts the live processes among names.
+func (m Model) countRunningIn(names []string) int {
+	count := 0
+	for _, name := range names {
+		if m.states[name].Status.IsRunning() {
+			count++
+		}
+	}
+	return count
+}
+
+// countStartableIn counts the processes among names that starting them
+// would launch, leaving out those already running, starting, or waiting for
+// their schedule.
+func (m Model) countStartableIn(names []string) int {
+	count := 0
+	for _, name := range names {
+		switch status := m.states[name].Status; {
+		case status.IsRunning(), status == process.StatusStarting, status == process.StatusScheduled:
+		default:
+			count++
+		}
+	}
+	return count
+}
+
+// countRunning counts live processes, including those that have passed their
+// health check.
+func (m Model) countRunning() int {
+	count := 0
+	for _, s := range m.states {
+		if s.Status.IsRunning() {
+			count++
+		}
+	}
+	return count
+}
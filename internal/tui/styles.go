@@ -9,27 +9,77 @@ import (
 )
 
 var (
-	colorRunning  = lipgloss.AdaptiveColor{Light: "#2ECC71", Dark: "#2ECC71"}
-	colorFailed   = lipgloss.AdaptiveColor{Light: "#E74C3C", Dark: "#E74C3C"}
-	colorRetrying = lipgloss.AdaptiveColor{Light: "#F39C12", Dark: "#F39C12"}
-	colorStopped  = lipgloss.AdaptiveColor{Light: "#7F8C8D", Dark: "#7F8C8D"}
-	colorStarting = lipgloss.AdaptiveColor{Light: "#3498DB", Dark: "#3498DB"}
-
-	colorAccent = lipgloss.AdaptiveColor{Light: "#10B981", Dark: "#10B981"}
-	colorSubtle = lipgloss.AdaptiveColor{Light: "#666666", Dark: "#666666"}
-	colorDim    = lipgloss.AdaptiveColor{Light: "#999999", Dark: "#555555"}
+	colorRunning   lipgloss.TerminalColor
+	colorHealthy   lipgloss.TerminalColor
+	colorFailed    lipgloss.TerminalColor
+	colorRetrying  lipgloss.TerminalColor
+	colorStopped   lipgloss.TerminalColor
+	colorCompleted lipgloss.TerminalColor
+	colorStarting  lipgloss.TerminalColor
+
+	colorAccent lipgloss.TerminalColor
+	colorSubtle lipgloss.TerminalColor
+	colorDim    lipgloss.TerminalColor
+
+	searchMatchStyle lipgloss.Style
 )
 
+// tagColors are cycled through to tell processes apart in the combined log
+// view.
+var tagColors = []lipgloss.TerminalColor{
+	lipgloss.ANSIColor(6), // cyan
+	lipgloss.ANSIColor(3), // yellow
+	lipgloss.ANSIColor(2), // green
+	lipgloss.ANSIColor(5), // magenta
+	lipgloss.ANSIColor(4), // blue
+	lipgloss.ANSIColor(1), // red
+}
+
+func init() {
+	applyTheme(nil)
+}
+
+// applyTheme resets the colors to their defaults and then applies any
+// overrides from the config's theme section, keyed by config.ThemeColors name.
+func applyTheme(theme map[string]string) {
+	pick := func(name string, def lipgloss.TerminalColor) lipgloss.TerminalColor {
+		if c, ok := theme[name]; ok && c != "" {
+			return lipgloss.Color(c)
+		}
+		return def
+	}
+
+	colorRunning = pick("running", lipgloss.AdaptiveColor{Light: "#2ECC71", Dark: "#2ECC71"})
+	colorHealthy = pick("healthy", lipgloss.AdaptiveColor{Light: "#27AE60", Dark: "#27AE60"})
+	colorFailed = pick("failed", lipgloss.AdaptiveColor{Light: "#E74C3C", Dark: "#E74C3C"})
+	colorRetrying = pick("retrying", lipgloss.AdaptiveColor{Light: "#F39C12", Dark: "#F39C12"})
+	colorStopped = pick("stopped", lipgloss.AdaptiveColor{Light: "#7F8C8D", Dark: "#7F8C8D"})
+	colorCompleted = pick("completed", lipgloss.AdaptiveColor{Light: "#16A085", Dark: "#1ABC9C"})
+	colorStarting = pick("starting", lipgloss.AdaptiveColor{Light: "#3498DB", Dark: "#3498DB"})
+
+	colorAccent = pick("accent", lipgloss.AdaptiveColor{Light: "#10B981", Dark: "#10B981"})
+	colorSubtle = pick("subtle", lipgloss.AdaptiveColor{Light: "#666666", Dark: "#666666"})
+	colorDim = pick("dim", lipgloss.AdaptiveColor{Light: "#999999", Dark: "#555555"})
+
+	searchMatchStyle = lipgloss.NewStyle().
+		Background(colorRetrying).
+		Foreground(lipgloss.Color("#000000"))
+}
+
 func statusStyle(status process.Status) lipgloss.Style {
 	switch status {
 	case process.StatusRunning:
 		return lipgloss.NewStyle().Foreground(colorRunning)
+	case process.StatusHealthy:
+		return lipgloss.NewStyle().Foreground(colorHealthy)
 	case process.StatusFailed:
 		return lipgloss.NewStyle().Foreground(colorFailed)
 	case process.StatusRetrying:
 		return lipgloss.NewStyle().Foreground(colorRetrying)
-	case process.StatusStarting:
+	case process.StatusStarting, process.StatusScheduled:
 		return lipgloss.NewStyle().Foreground(colorStarting)
+	case process.StatusCompleted:
+		return lipgloss.NewStyle().Foreground(colorCompleted)
 	default:
 		return lipgloss.NewStyle().Foreground(colorStopped)
 	}
@@ -39,8 +89,12 @@ func statusIcon(status process.Status) string {
 	switch status {
 	case process.StatusRunning:
 		return "●"
+	case process.StatusHealthy:
+		return "◉"
 	case process.StatusStopped:
 		return "○"
+	case process.StatusCompleted:
+		return "✓"
 	case process.StatusFailed:
 		return "✗"
 	case process.StatusRetrying:
@@ -49,6 +103,8 @@ func statusIcon(status process.Status) string {
 		return "◐"
 	case process.StatusStopping:
 		return "◑"
+	case process.StatusScheduled:
+		return "◷"
 	default:
 		return "○"
 	}
@@ -63,3 +119,45 @@ func formatUptime(d time.Duration) string {
 	}
 	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
 }
+
+// formatRetry describes a pending retry, e.g. "retry #2 in 7s". The 1s tick
+// re-renders the row, so the countdown stays live.
+func formatRetry(state process.ProcessState) string {
+	wait := time.Until(state.NextRetryAt)
+	if state.NextRetryAt.IsZero() || wait <= 0 {
+		return "retrying…"
+	}
+	return fmt.Sprintf("retry #%d in %s", state.RetryCount, formatUptime(wait+time.Second-1))
+}
+
+// formatFailed describes a failed process, with its exit code when it exited
+// on its own, e.g. "failed (exit 127)".
+func formatFailed(state process.ProcessState) string {
+	if state.ExitCode == 0 {
+		return "failed"
+	}
+	return fmt.Sprintf("failed (exit %d)", state.ExitCode)
+}
+
+// formatNextRun describes when a scheduled process runs next, e.g.
+// "next in 4m12s".
+func formatNextRun(state process.ProcessState) string {
+	wait := time.Until(state.NextRunAt)
+	if state.NextRunAt.IsZero() || wait <= 0 {
+		return "scheduled"
+	}
+	return "next in " + formatUptime(wait+time.Second-1)
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(b)/float64(div), "KMGTPE"[exp])
+}
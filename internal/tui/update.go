@@ -1,12 +1,23 @@
 package tui
 
 import (
+	"fmt"
+	"log/slog"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/frontendtony/shepherd/internal/session"
+)
+
+const (
+	// notifyDuration is how long a status bar notification stays visible.
+	notifyDuration = 3 * time.Second
+	// errDuration is how long an error stays in the status bar.
+	errDuration = 5 * time.Second
 )
 
 // Update implements tea.Model.
@@ -20,6 +31,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.ready {
 			m.logViewport = viewport.New(m.logPanelInnerWidth(), m.panelContentHeight())
 			m.ready = true
+			m.resizePTYs()
 		} else {
 			m.resizeViewport()
 		}
@@ -27,14 +39,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case stateEventMsg:
 		m.refreshStates()
-		m.err = nil
-		cmds = append(cmds, listenForEvents(m.manager))
+		m.reorderItems()
+		cmds = append(cmds, listenForEvents(m.events))
 
 	case tickMsg:
 		m.refreshStates()
+		m.reorderItems()
 		m.updateLogContent()
-		// Auto-clear error after 5 seconds.
-		if m.err != nil && !m.errSetAt.IsZero() && time.Since(m.errSetAt) > 5*time.Second {
+		// Auto-clear error once it has been shown long enough.
+		if m.err != nil && !m.errSetAt.IsZero() && time.Since(m.errSetAt) > errDuration {
 			m.err = nil
 		}
 		// Auto-clear notification.
@@ -49,35 +62,60 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ConfigReloadMsg:
 		m.config = msg.Config
+		applyKeybindings(m.config.Keybindings)
+		applyTheme(m.config.Theme)
 		m.groups = nil
 		m.buildGroups()
 		m.rebuildItems()
-		if m.selectedIdx >= len(m.items) {
-			m.selectedIdx = 0
+		m.restoreSelection()
+		m.refreshStates()
+		m.notify("Config reloaded")
+		m.confirmRestart = nil
+		for _, name := range msg.Changed {
+			s := m.states[name]
+			if s.Status.IsRunning() || s.Status == process.StatusStarting || s.Status == process.StatusRetrying {
+				m.confirmRestart = append(m.confirmRestart, name)
+			}
 		}
-		m.notification = "Config reloaded"
-		m.notifyUntil = time.Now().Add(3 * time.Second)
 
 	case NotifyMsg:
-		m.notification = msg.Text
-		m.notifyUntil = time.Now().Add(3 * time.Second)
+		m.notify(msg.Text)
 
 	case tea.KeyMsg:
 		cmd := m.handleKey(msg)
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+
+	default:
+		// Forward cursor blink and similar messages to the active input.
+		if m.filtering {
+			var cmd tea.Cmd
+			m.filterInput, cmd = m.filterInput.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.searching {
+			var cmd tea.Cmd
+			m.searchInput, cmd = m.searchInput.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// notify shows text in the status bar until notifyDuration elapses; the tick
+// handler clears it.
+func (m *Model) notify(text string) {
+	m.notification = text
+	m.notifyUntil = time.Now().Add(notifyDuration)
+}
+
 func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 	// Confirmation modes take priority.
 	if m.confirmQuit {
 		if msg.String() == "y" {
-			m.manager.Shutdown()
-			return tea.Quit
+			return m.quit()
 		}
 		m.confirmQuit = false
 		return nil
@@ -91,6 +129,50 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		return nil
 	}
 
+	if m.confirmStopGrp != "" {
+		name := m.confirmStopGrp
+		m.confirmStopGrp = ""
+		g := m.groupByName(name)
+		if msg.String() == "y" && g != nil {
+			m.notify(fmt.Sprintf("Stopping %d process(es) in %s…", m.countRunningIn(g.processes), name))
+			return stopGroupCmd(m.manager, g.processes)
+		}
+		return nil
+	}
+
+	if m.confirmStop != "" {
+		name, cmd := m.confirmStop, m.confirmCmd
+		m.confirmStop, m.confirmCmd = "", nil
+		if msg.String() == "y" {
+			return cmd(m.manager, name)
+		}
+		return nil
+	}
+	if m.confirmRestore != nil {
+		names := m.confirmRestore
+		m.confirmRestore = nil
+		if msg.String() == "y" {
+			return startGroupCmd(m.manager, "restore", names)
+		}
+		return nil
+	}
+	if m.confirmRestart != nil {
+		names := m.confirmRestart
+		m.confirmRestart = nil
+		if msg.String() == "y" {
+			return restartChangedCmd(m.manager, names)
+		}
+		return nil
+	}
+
+	// Filter and search inputs capture all keys while open.
+	if m.filtering {
+		return m.handleFilterKey(msg)
+	}
+	if m.searching {
+		return m.handleSearchKey(msg)
+	}
+
 	// Help overlay.
 	if m.showHelp {
 		if key.Matches(msg, keys.Help) || msg.String() == "esc" {
@@ -99,6 +181,14 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		return nil
 	}
 
+	// Inspector overlay.
+	if m.showInspector {
+		if key.Matches(msg, keys.Inspect) || msg.String() == "esc" {
+			m.showInspector = false
+		}
+		return nil
+	}
+
 	// Full-screen log view.
 	if m.fullScreenLogs {
 		return m.handleFullScreenKey(msg)
@@ -115,11 +205,46 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 
 func (m *Model) handleFullScreenKey(msg tea.KeyMsg) tea.Cmd {
 	switch {
+	case msg.String() == "esc" && m.search != "":
+		m.clearSearch()
 	case key.Matches(msg, keys.FullScreen) || msg.String() == "esc":
 		m.fullScreenLogs = false
 		m.resizeViewport()
+	case key.Matches(msg, keys.AllLogs):
+		m.toggleAllLogs()
 	case key.Matches(msg, keys.Quit):
 		return m.handleQuit()
+	case key.Matches(msg, keys.Filter):
+		m.searching = true
+		return m.searchInput.Focus()
+	case key.Matches(msg, keys.NextMatch):
+		m.jumpToMatch(1)
+	case key.Matches(msg, keys.PrevMatch):
+		m.jumpToMatch(-1)
+	case key.Matches(msg, keys.Colors):
+		m.toggleColors()
+	case key.Matches(msg, keys.Levels):
+		m.toggleLevelColors()
+	case key.Matches(msg, keys.Timestamps):
+		m.hideTimestamps = !m.hideTimestamps
+		m.updateLogContent()
+	case key.Matches(msg, keys.Wrap):
+		m.toggleWrap()
+	case key.Matches(msg, keys.Export):
+		return m.exportLogs()
+	case key.Matches(msg, keys.ClearLogs):
+		m.clearLogs()
+	case key.Matches(msg, keys.Follow):
+		m.autoScroll = !m.autoScroll
+		if m.autoScroll {
+			m.logViewport.GotoBottom()
+		}
+	case key.Matches(msg, keys.Top):
+		m.autoScroll = false
+		m.logViewport.GotoTop()
+	case key.Matches(msg, keys.Bottom):
+		m.autoScroll = true
+		m.logViewport.GotoBottom()
 	default:
 		var cmd tea.Cmd
 		m.logViewport, cmd = m.logViewport.Update(msg)
@@ -136,10 +261,45 @@ func (m *Model) handleLogPanelKey(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, keys.FullScreen):
 		m.fullScreenLogs = true
 		m.resizeViewport()
+	case key.Matches(msg, keys.AllLogs):
+		m.toggleAllLogs()
 	case key.Matches(msg, keys.Quit):
 		return m.handleQuit()
 	case key.Matches(msg, keys.Help):
 		m.showHelp = true
+	case key.Matches(msg, keys.Filter):
+		m.searching = true
+		return m.searchInput.Focus()
+	case key.Matches(msg, keys.NextMatch):
+		m.jumpToMatch(1)
+	case key.Matches(msg, keys.PrevMatch):
+		m.jumpToMatch(-1)
+	case key.Matches(msg, keys.Colors):
+		m.toggleColors()
+	case key.Matches(msg, keys.Levels):
+		m.toggleLevelColors()
+	case key.Matches(msg, keys.Timestamps):
+		m.hideTimestamps = !m.hideTimestamps
+		m.updateLogContent()
+	case key.Matches(msg, keys.Wrap):
+		m.toggleWrap()
+	case key.Matches(msg, keys.Export):
+		return m.exportLogs()
+	case key.Matches(msg, keys.ClearLogs):
+		m.clearLogs()
+	case key.Matches(msg, keys.Follow):
+		m.autoScroll = !m.autoScroll
+		if m.autoScroll {
+			m.logViewport.GotoBottom()
+		}
+	case key.Matches(msg, keys.Top):
+		m.autoScroll = false
+		m.logViewport.GotoTop()
+	case key.Matches(msg, keys.Bottom):
+		m.autoScroll = true
+		m.logViewport.GotoBottom()
+	case msg.String() == "esc" && m.search != "":
+		m.clearSearch()
 	default:
 		var cmd tea.Cmd
 		m.logViewport, cmd = m.logViewport.Update(msg)
@@ -149,12 +309,168 @@ func (m *Model) handleLogPanelKey(msg tea.KeyMsg) tea.Cmd {
 	return nil
 }
 
+func (m *Model) handleFilterKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.filtering = false
+		m.filterInput.Blur()
+		m.filterInput.Reset()
+		m.applyFilter("")
+		return nil
+	case "enter":
+		m.filtering = false
+		m.filterInput.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	m.applyFilter(m.filterInput.Value())
+	return cmd
+}
+
+func (m *Model) handleSearchKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.clearSearch()
+		return nil
+	case "enter":
+		m.searching = false
+		m.searchInput.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.search = m.searchInput.Value()
+	m.matchIdx = 0
+	m.updateLogContent()
+	m.jumpToMatch(0)
+	return cmd
+}
+
+// toggleColors switches the log view between rendered ANSI colors and plain text.
+func (m *Model) toggleColors() {
+	m.plainLogs = !m.plainLogs
+	m.updateLogContent()
+	if m.plainLogs {
+		m.notify("Log colors off")
+	} else {
+		m.notify("Log colors on")
+	}
+}
+
+// toggleLevelColors switches coloring log lines by their detected level.
+func (m *Model) toggleLevelColors() {
+	m.noLevelColors = !m.noLevelColors
+	m.updateLogContent()
+	if m.noLevelColors {
+		m.notify("Level colors off")
+	} else {
+		m.notify("Level colors on")
+	}
+}
+
+// toggleWrap switches the log view between wrapping and truncating long lines.
+func (m *Model) toggleWrap() {
+	m.wrapLogs = !m.wrapLogs
+	m.updateLogContent()
+	if m.wrapLogs {
+		m.notify("Line wrap on")
+	} else {
+		m.notify("Line wrap off")
+	}
+}
+
+// toggleAllLogs switches the log view between the selected process and every
+// process interleaved, following the tail either way.
+func (m *Model) toggleAllLogs() {
+	m.allLogs = !m.allLogs
+	m.autoScroll = true
+	m.updateLogContent()
+	m.logViewport.GotoBottom()
+	if m.allLogs {
+		m.notify("Showing all logs")
+	} else {
+		m.notify("Showing " + m.selectedProc + " logs")
+	}
+}
+
+// exportLogs saves the selected process's logs to a file in the home directory.
+func (m *Model) exportLogs() tea.Cmd {
+	if m.selectedProc == "" {
+		return nil
+	}
+	return exportLogsCmd(m.manager, m.selectedProc, !m.hideTimestamps)
+}
+
+// clearLogs empties the log buffers on screen: the selected process's, or
+// every process's in the combined view.
+func (m *Model) clearLogs() {
+	var names []string
+	if m.allLogs {
+		for name := range m.config.Processes {
+			names = append(names, name)
+		}
+	} else {
+		names = []string{m.selectedProc}
+	}
+	for _, name := range names {
+		if buf := m.manager.GetLogBuffer(name); buf != nil {
+			buf.Clear()
+		}
+	}
+	m.autoScroll = true
+	m.updateLogContent()
+	m.notify("Logs cleared")
+}
+
+// clearSearch closes the log search input and removes highlighting.
+func (m *Model) clearSearch() {
+	m.searching = false
+	m.searchInput.Blur()
+	m.searchInput.Reset()
+	m.search = ""
+	m.updateLogContent()
+}
+
+// reorderItems rebuilds the list when its order or contents depend on process
+// state, so it tracks status changes.
+func (m *Model) reorderItems() {
+	if m.sortMode == sortByName && m.statusFilter == showAll {
+		return
+	}
+	m.rebuildItems()
+	m.restoreSelection()
+}
+
+// applyFilter narrows the process list to entries matching filter.
+func (m *Model) applyFilter(filter string) {
+	m.filter = filter
+	m.rebuildItems()
+	m.restoreSelection()
+}
+
 func (m *Model) handleProcessListKey(msg tea.KeyMsg) tea.Cmd {
 	switch {
 	case key.Matches(msg, keys.Quit):
 		return m.handleQuit()
 	case key.Matches(msg, keys.Help):
 		m.showHelp = true
+	case key.Matches(msg, keys.Filter):
+		m.filtering = true
+		return m.filterInput.Focus()
+	case msg.String() == "esc" && m.filter != "":
+		m.filterInput.Reset()
+		m.applyFilter("")
+	case key.Matches(msg, keys.Sort):
+		m.sortMode = (m.sortMode + 1) % 3
+		m.applyFilter(m.filter)
+		m.notify("Sort by " + m.sortMode.String())
+	case key.Matches(msg, keys.ShowStatus):
+		m.statusFilter = (m.statusFilter + 1) % 3
+		m.applyFilter(m.filter)
+		m.notify("Showing " + m.statusFilter.String() + " processes")
 	case key.Matches(msg, keys.Up):
 		if m.selectedIdx > 0 {
 			m.selectedIdx--
@@ -174,39 +490,72 @@ func (m *Model) handleProcessListKey(msg tea.KeyMsg) tea.Cmd {
 				if m.selectedIdx >= len(m.items) {
 					m.selectedIdx = len(m.items) - 1
 				}
+			} else {
+				m.showInspector = true
 			}
 		}
+	case key.Matches(msg, keys.Inspect):
+		if m.selectedIdx < len(m.items) && !m.items[m.selectedIdx].isGroup {
+			m.showInspector = true
+		}
 	case key.Matches(msg, keys.Start):
 		if m.selectedIdx < len(m.items) && !m.items[m.selectedIdx].isGroup {
 			return startProcessCmd(m.manager, m.items[m.selectedIdx].name)
 		}
 	case key.Matches(msg, keys.Stop):
 		if m.selectedIdx < len(m.items) && !m.items[m.selectedIdx].isGroup {
-			return stopProcessCmd(m.manager, m.items[m.selectedIdx].name)
+			return m.stopSelected("Stop %s?", stopProcessCmd)
+		}
+	case key.Matches(msg, keys.StopOnly):
+		if m.selectedIdx < len(m.items) && !m.items[m.selectedIdx].isGroup {
+			return m.stopSelected("Stop %s without its dependents?", stopProcessOnlyCmd)
+		}
+	case key.Matches(msg, keys.Kill):
+		if m.selectedIdx < len(m.items) && !m.items[m.selectedIdx].isGroup {
+			return m.stopSelected("Kill %s?", killProcessCmd)
 		}
 	case key.Matches(msg, keys.Restart):
 		if m.selectedIdx < len(m.items) && !m.items[m.selectedIdx].isGroup {
 			return restartProcessCmd(m.manager, m.items[m.selectedIdx].name)
 		}
+	case key.Matches(msg, keys.SigUsr1):
+		if m.selectedIdx < len(m.items) && !m.items[m.selectedIdx].isGroup {
+			return signalProcessCmd(m.manager, m.items[m.selectedIdx].name, syscall.SIGUSR1)
+		}
+	case key.Matches(msg, keys.SigUsr2):
+		if m.selectedIdx < len(m.items) && !m.items[m.selectedIdx].isGroup {
+			return signalProcessCmd(m.manager, m.items[m.selectedIdx].name, syscall.SIGUSR2)
+		}
 	case key.Matches(msg, keys.StartGrp):
 		if g := m.selectedGroup(); g != nil {
-			return startGroupCmd(m.manager, g.processes)
+			if n := m.countStartableIn(g.processes); n > 0 {
+				m.notify(fmt.Sprintf("Starting %d process(es) in %s…", n, g.name))
+			}
+			return startGroupCmd(m.manager, g.name, g.processes)
 		}
 	case key.Matches(msg, keys.StopGrp):
+		if g := m.selectedGroup(); g != nil && m.countRunningIn(g.processes) > 0 {
+			m.confirmStopGrp = g.name
+		}
+	case key.Matches(msg, keys.RestartGrp):
 		if g := m.selectedGroup(); g != nil {
-			return stopGroupCmd(m.manager, g.processes)
+			return restartGroupCmd(m.manager, g.processes)
 		}
 	case key.Matches(msg, keys.StartAll):
-		return startAllCmd(m.manager, m.config)
+		return startAllCmd(m.manager)
 	case key.Matches(msg, keys.StopAll):
-		if m.countByStatus(process.StatusRunning) > 0 {
+		if m.countRunning() > 0 {
 			m.confirmStopAll = true
 		}
+	case key.Matches(msg, keys.Export):
+		return m.exportLogs()
 	case key.Matches(msg, keys.Tab), key.Matches(msg, keys.Logs):
 		m.focusedPanel = PanelLogs
 	case key.Matches(msg, keys.FullScreen):
 		m.fullScreenLogs = true
 		m.resizeViewport()
+	case key.Matches(msg, keys.AllLogs):
+		m.toggleAllLogs()
 	}
 	return nil
 }
@@ -214,7 +563,7 @@ func (m *Model) handleProcessListKey(msg tea.KeyMsg) tea.Cmd {
 func (m *Model) handleQuit() tea.Cmd {
 	running := 0
 	for _, s := range m.states {
-		if s.Status == process.StatusRunning || s.Status == process.StatusStarting ||
+		if s.Status.IsRunning() || s.Status == process.StatusStarting ||
 			s.Status == process.StatusRetrying {
 			running++
 		}
@@ -223,18 +572,86 @@ func (m *Model) handleQuit() tea.Cmd {
 		m.confirmQuit = true
 		return nil
 	}
+	return m.quit()
+}
+
+// quit records the running set for the next launch when session restore is
+// enabled, then stops everything and exits.
+func (m *Model) quit() tea.Cmd {
+	if m.config.Session.Restore {
+		if err := session.Save(m.config.Session.File, m.manager.RunningNames()); err != nil {
+			slog.Warn("failed to save session", "error", err)
+		}
+	}
+	m.unsubscribe()
+	m.manager.Shutdown()
 	return tea.Quit
 }
 
+// updateSelectedProc switches the log view to the selected process. The scroll
+// position of the process being left is remembered if the user had scrolled
+// away from the tail, and restored when it is selected again.
 func (m *Model) updateSelectedProc() {
-	if m.selectedIdx >= 0 && m.selectedIdx < len(m.items) {
-		item := m.items[m.selectedIdx]
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.items) {
+		return
+	}
+	item := m.items[m.selectedIdx]
+	if item.isGroup || item.name == m.selectedProc {
+		return
+	}
+	// The combined view doesn't depend on the selection.
+	if m.allLogs {
+		m.selectedProc = item.name
+		return
+	}
+
+	if m.selectedProc != "" {
+		if m.autoScroll {
+			delete(m.scrollPos, m.selectedProc)
+		} else {
+			m.scrollPos[m.selectedProc] = m.logViewport.YOffset
+		}
+	}
+
+	m.selectedProc = item.name
+	offset, scrolled := m.scrollPos[item.name]
+	m.autoScroll = !scrolled
+	m.updateLogContent()
+	if scrolled {
+		m.logViewport.SetYOffset(offset)
+	}
+}
+
+// restoreSelection re-selects the previously selected process by name after
+// the item list has been rebuilt. If it no longer exists, the first process
+// is selected instead.
+func (m *Model) restoreSelection() {
+	for i, item := range m.items {
+		if !item.isGroup && item.name == m.selectedProc {
+			m.selectedIdx = i
+			return
+		}
+	}
+
+	m.selectedIdx = 0
+	m.selectedProc = ""
+	for i, item := range m.items {
 		if !item.isGroup {
-			m.selectedProc = item.name
-			m.autoScroll = true
-			m.updateLogContent()
+			m.selectedIdx = i
+			break
 		}
 	}
+	m.updateSelectedProc()
+}
+
+// groupByName returns the group shown under name, or nil.
+func (m Model) groupByName(name string) *groupView {
+	for i := range m.groups {
+		if m.groups[i].name == name {
+			return &m.groups[i]
+		}
+	}
+	return nil
 }
 
 func (m Model) selectedGroup() *groupView {
@@ -249,33 +666,71 @@ func (m Model) selectedGroup() *groupView {
 	return nil
 }
 
+// Below this size the panels can't be laid out, and View shows a message
+// instead.
+const (
+	minWidth  = 40
+	minHeight = 6
+)
+
+// tooSmall reports whether the terminal is below the minimum usable size.
+func (m Model) tooSmall() bool {
+	return m.width < minWidth || m.height < minHeight
+}
+
+// The dimension helpers never return less than 1, so a tiny terminal can't
+// produce negative widths or heights for lipgloss or the viewport.
+
 func (m Model) listPanelWidth() int {
 	w := m.width * 2 / 5
 	if w < 25 {
 		w = 25
 	}
-	return w
+	return max(min(w, m.width-1), 1)
 }
 
 func (m Model) logPanelWidth() int {
-	return m.width - m.listPanelWidth()
+	return max(m.width-m.listPanelWidth(), 1)
 }
 
 func (m Model) logPanelInnerWidth() int {
-	return m.logPanelWidth() - 4
+	return max(m.logPanelWidth()-4, 1)
 }
 
 func (m Model) panelContentHeight() int {
-	return m.height - 3
+	return max(m.height-3, 1)
 }
 
 func (m *Model) resizeViewport() {
 	if m.fullScreenLogs {
-		m.logViewport.Width = m.width
-		m.logViewport.Height = m.height - 3
+		m.logViewport.Width = max(m.width, 1)
+		m.logViewport.Height = max(m.height-3, 1)
 	} else {
 		m.logViewport.Width = m.logPanelInnerWidth()
 		m.logViewport.Height = m.panelContentHeight()
 	}
+	m.resizePTYs()
 	m.updateLogContent()
 }
+
+// resizePTYs sizes process terminals to the log viewport, so programs that
+// adapt to the terminal width render to fit it.
+func (m *Model) resizePTYs() {
+	m.manager.ResizePTYs(m.logViewport.Width, m.logViewport.Height)
+}
+
+// stopFunc stops a process one way or another, e.g. stopProcessCmd.
+type stopFunc func(mgr *process.ProcessManager, name string) tea.Cmd
+
+// stopSelected runs cmd for the selected process, or, if the process has
+// confirm_stop set, asks prompt (formatted with its name) first.
+func (m *Model) stopSelected(prompt string, cmd stopFunc) tea.Cmd {
+	name := m.items[m.selectedIdx].name
+	if m.config.Processes[name].ConfirmStop {
+		m.confirmStop = name
+		m.confirmPrompt = fmt.Sprintf(prompt, name)
+		m.confirmCmd = cmd
+		return nil
+	}
+	return cmd(m.manager, name)
+}
@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/frontendtony/shepherd/internal/process"
 )
 
 // View implements tea.Model.
@@ -10,10 +13,22 @@ func (m Model) View() string {
 		return "Initializing..."
 	}
 
+	if m.tooSmall() {
+		return lipgloss.NewStyle().
+			Foreground(colorDim).
+			MaxWidth(m.width).
+			MaxHeight(m.height).
+			Render(fmt.Sprintf("Terminal too small (%dx%d); need at least %dx%d", m.width, m.height, minWidth, minHeight))
+	}
+
 	if m.showHelp {
 		return m.renderHelp()
 	}
 
+	if m.showInspector {
+		return m.renderInspector()
+	}
+
 	if m.fullScreenLogs {
 		return m.renderFullScreenLogs()
 	}
@@ -33,9 +48,15 @@ func (m Model) View() string {
 
 func (m Model) renderFullScreenLogs() string {
 	header := "Logs"
-	if m.selectedProc != "" {
+	if m.allLogs {
+		header = "Logs: all processes"
+	} else if m.selectedProc != "" {
 		state := m.states[m.selectedProc]
 		header = "Logs: " + m.selectedProc + " [" + string(state.Status) + "]"
+		// The 1s tick refreshes states and re-renders, so this stays live.
+		if state.Status.IsRunning() || state.Status == process.StatusStopping {
+			header += " up " + formatUptime(state.Uptime())
+		}
 	}
 
 	headerStyle := lipgloss.NewStyle().
@@ -44,7 +65,12 @@ func (m Model) renderFullScreenLogs() string {
 
 	footer := lipgloss.NewStyle().
 		Foreground(colorDim).
-		Render("f close  ↑/↓ scroll  q quit")
+		Render(fmt.Sprintf("%s close  ↑/↓ scroll  %s search  %s/%s next/prev  %s quit",
+			shortKey(keys.FullScreen), shortKey(keys.Filter),
+			shortKey(keys.NextMatch), shortKey(keys.PrevMatch), shortKey(keys.Quit)))
+	if m.searching || m.search != "" {
+		footer = m.renderSearchLine()
+	}
 
 	contentHeight := m.height - 3 // header + footer + border spacing
 	content := m.logViewport.View()
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"app": {Command: "true"},
+		},
+	}
+	mgr, err := process.NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(mgr.Shutdown)
+	return NewModel(mgr, cfg, "", nil)
+}
+
+func resize(m Model, width, height int) Model {
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return updated.(Model)
+}
+
+func TestView_TinyWindow(t *testing.T) {
+	sizes := []struct{ width, height int }{
+		{0, 0}, {1, 1}, {10, 3}, {minWidth - 1, 24}, {80, minHeight - 1},
+	}
+	for _, size := range sizes {
+		m := newTestModel(t)
+		m = resize(m, size.width, size.height)
+		assert.GreaterOrEqual(t, m.logViewport.Width, 1)
+		assert.GreaterOrEqual(t, m.logViewport.Height, 1)
+		assert.NotPanics(t, func() { m.View() })
+		if size.width >= 20 {
+			assert.Contains(t, m.View(), "too small")
+		}
+
+		// Growing back to a usable size renders the panels again.
+		m = resize(m, 80, 24)
+		assert.NotContains(t, m.View(), "too small")
+	}
+}
+
+func TestView_ShrinkFromNormalSize(t *testing.T) {
+	m := newTestModel(t)
+	m = resize(m, 80, 24)
+	m.fullScreenLogs = true
+	for _, h := range []int{5, 2, 1, 0} {
+		m = resize(m, 20, h)
+		assert.GreaterOrEqual(t, m.logViewport.Height, 1)
+		assert.NotPanics(t, func() { m.View() })
+	}
+}
+
+func TestPanelDimensionsClamped(t *testing.T) {
+	m := Model{width: 3, height: 1}
+	assert.Equal(t, 2, m.listPanelWidth())
+	assert.Equal(t, 1, m.logPanelWidth())
+	assert.Equal(t, 1, m.logPanelInnerWidth())
+	assert.Equal(t, 1, m.panelContentHeight())
+}
+
+func TestAllLogLines_InterleavesByTime(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]config.Process{
+			"api":     {Command: "true"},
+			"bastion": {Command: "true"},
+		},
+	}
+	mgr, err := process.NewProcessManager(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(mgr.Shutdown)
+	m := NewModel(mgr, cfg, "", nil)
+	m.hideTimestamps = true
+	m.plainLogs = true
+
+	for _, w := range []struct{ name, line string }{
+		{"bastion", "tunnel up"},
+		{"api", "listening"},
+		{"bastion", "client connected"},
+	} {
+		mgr.GetLogBuffer(w.name).Write([]byte(w.line + "\n"))
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	lines := m.allLogLines()
+	require.Len(t, lines, 3)
+	assert.Equal(t, "bastion | tunnel up", stripANSI(lines[0]))
+	assert.Equal(t, "api     | listening", stripANSI(lines[1]))
+	assert.Equal(t, "bastion | client connected", stripANSI(lines[2]))
+}
+
+func TestRenderProcessRow_FailedShowsExitCode(t *testing.T) {
+	m := resize(newTestModel(t), 140, 24)
+	m.states["app"] = process.ProcessState{Name: "app", Status: process.StatusFailed, ExitCode: 127, LastError: "exit status 127"}
+
+	row := stripANSI(m.renderProcessRow(listItem{name: "app"}, 40))
+	assert.Contains(t, row, "failed (exit 127)")
+	assert.Contains(t, stripANSI(m.renderStatusBar()), "app: exit status 127")
+
+	m.states["app"] = process.ProcessState{Name: "app", Status: process.StatusFailed, LastError: "dependency db failed"}
+	row = stripANSI(m.renderProcessRow(listItem{name: "app"}, 40))
+	assert.True(t, strings.HasSuffix(row, " failed"), row)
+}
+
+func TestStopGroup_AsksForConfirmation(t *testing.T) {
+	m := resize(newTestModel(t), 140, 24)
+	m.states["app"] = process.ProcessState{Name: "app", Status: process.StatusRunning}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	m = updated.(Model)
+	assert.Equal(t, "other", m.confirmStopGrp)
+	assert.Contains(t, stripANSI(m.renderStatusBar()), "Stop 1 running process(es) in other? (y/n)")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = updated.(Model)
+	assert.Empty(t, m.confirmStopGrp)
+	assert.Equal(t, process.StatusRunning, m.states["app"].Status)
+}
+
+func TestHints_FollowRebinding(t *testing.T) {
+	m := resize(newTestModel(t), 140, 24)
+	applyKeybindings(map[string]config.KeyList{"stop": {"d"}, "bottom": {"b"}})
+	t.Cleanup(func() { applyKeybindings(nil) })
+
+	bar := stripANSI(m.renderStatusBar())
+	assert.Contains(t, bar, "d stop")
+	assert.NotContains(t, bar, "x stop")
+
+	m.showHelp = true
+	help := stripANSI(m.renderHelp())
+	assert.Contains(t, help, "d       Stop selected process")
+	assert.Contains(t, help, "(b resumes following)")
+}
+
+func TestConfirmStop_CoversStopOnlyAndKill(t *testing.T) {
+	m := resize(newTestModel(t), 140, 24)
+	m.config.Processes["app"] = config.Process{Command: "true", ConfirmStop: true}
+
+	for _, tc := range []struct {
+		key    tea.KeyMsg
+		prompt string
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, "Stop app? (y/n)"},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x"), Alt: true}, "Stop app without its dependents? (y/n)"},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")}, "Kill app? (y/n)"},
+	} {
+		updated, cmd := m.Update(tc.key)
+		m = updated.(Model)
+		assert.Nil(t, cmd, tc.prompt)
+		assert.Equal(t, "app", m.confirmStop, tc.prompt)
+		assert.Contains(t, stripANSI(m.renderStatusBar()), tc.prompt)
+
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+		m = updated.(Model)
+		assert.Empty(t, m.confirmStop)
+	}
+}
+
+func TestStartGroupCmd_SumsUpResult(t *testing.T) {
+	m := newTestModel(t)
+
+	msg := startGroupCmd(m.manager, "restore", []string{"app"})()
+	assert.Equal(t, NotifyMsg{Text: "restore: started 1"}, msg)
+}
+
+func TestStatusRank_ScheduledBetweenHealthyAndStopped(t *testing.T) {
+	assert.Less(t, statusRank(process.StatusHealthy), statusRank(process.StatusScheduled))
+	assert.Less(t, statusRank(process.StatusScheduled), statusRank(process.StatusStopped))
+	assert.Equal(t, statusRank(process.StatusStopped), statusRank(process.StatusCompleted))
+}
+
+func TestStartGroup_CountsOnlyProcessesToStart(t *testing.T) {
+	m := resize(newTestModel(t), 140, 24)
+	m.states["app"] = process.ProcessState{Name: "app", Status: process.StatusRunning}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = updated.(Model)
+	assert.Empty(t, m.notification, "nothing to start")
+
+	m.states["app"] = process.ProcessState{Name: "app", Status: process.StatusStopped}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = updated.(Model)
+	assert.Equal(t, "Starting 1 process(es) in other…", m.notification)
+}
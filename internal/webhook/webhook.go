@@ -0,0 +1,157 @@
+// Package webhook posts process state changes to configured URLs.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/frontendtony/shepherd/internal/process"
+)
+
+const (
+	// maxAttempts is how many times a POST is tried before giving up.
+	maxAttempts = 3
+	// initialBackoff is the wait before the second attempt; it doubles after.
+	initialBackoff = time.Second
+)
+
+// Payload is the JSON body of a webhook POST.
+type Payload struct {
+	Name     string         `json:"name"`
+	OldState process.Status `json:"old_state"`
+	NewState process.Status `json:"new_state"`
+	Error    string         `json:"error,omitempty"`
+	Time     time.Time      `json:"time"`
+}
+
+// Dispatcher consumes state events and posts them to the webhooks subscribed
+// to each event.
+type Dispatcher struct {
+	hooks   []config.Webhook
+	client  *http.Client
+	backoff time.Duration
+
+	// down tracks processes that failed or are retrying, so their next
+	// transition to running can be reported as recovered.
+	down map[string]bool
+}
+
+// NewDispatcher returns a Dispatcher for the given webhooks.
+func NewDispatcher(hooks []config.Webhook) *Dispatcher {
+	return &Dispatcher{
+		hooks:   hooks,
+		client:  &http.Client{},
+		backoff: initialBackoff,
+		down:    make(map[string]bool),
+	}
+}
+
+// Run posts events until events is closed or ctx is done. Each POST runs in
+// its own goroutine so a slow endpoint doesn't hold up later events.
+func (d *Dispatcher) Run(ctx context.Context, events <-chan process.StateEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			d.dispatch(ctx, ev)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, ev process.StateEvent) {
+	event := d.classify(ev)
+	payload := Payload{
+		Name:     ev.Name,
+		OldState: ev.OldState,
+		NewState: ev.NewState,
+		Error:    ev.Error,
+		Time:     time.Now(),
+	}
+	for _, hook := range d.hooks {
+		if hook.Fires(string(ev.NewState)) || (event == config.WebhookRecovered && hook.Fires(event)) {
+			go d.post(ctx, hook, payload)
+		}
+	}
+}
+
+// classify returns config.WebhookRecovered when ev brings a failed or
+// retrying process back up, and updates the set of down processes.
+func (d *Dispatcher) classify(ev process.StateEvent) string {
+	switch {
+	case ev.NewState == process.StatusFailed || ev.NewState == process.StatusRetrying:
+		d.down[ev.Name] = true
+	case ev.NewState.IsRunning():
+		if d.down[ev.Name] {
+			delete(d.down, ev.Name)
+			return config.WebhookRecovered
+		}
+	case ev.NewState == process.StatusStopped || ev.NewState == process.StatusCompleted:
+		delete(d.down, ev.Name)
+	}
+	return ""
+}
+
+// post sends payload to hook, retrying with backoff on network errors and
+// 5xx or 429 responses.
+func (d *Dispatcher) post(ctx context.Context, hook config.Webhook, payload Payload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		slog.Warn("encoding webhook payload", "error", err)
+		return
+	}
+
+	backoff := d.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := d.send(ctx, hook, body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == maxAttempts {
+			slog.Warn("webhook failed", "url", hook.URL, "process", payload.Name, "attempts", attempt, "error", err)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send makes one POST. retry reports whether a failure is worth retrying.
+func (d *Dispatcher) send(ctx context.Context, hook config.Webhook, body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, hook.TimeoutDuration())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frontendtony/shepherd/internal/config"
+	"github.com/frontendtony/shepherd/internal/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a webhook endpoint that fails the first failures requests.
+type recorder struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	payloads []Payload
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.calls <= r.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var p Payload
+	json.NewDecoder(req.Body).Decode(&p)
+	r.payloads = append(r.payloads, p)
+}
+
+func (r *recorder) received() []Payload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Payload(nil), r.payloads...)
+}
+
+func TestDispatcher_FailedAndRecovered(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	d := NewDispatcher([]config.Webhook{{URL: srv.URL}})
+	events := make(chan process.StateEvent, 8)
+	events <- process.StateEvent{Name: "db", OldState: process.StatusStopped, NewState: process.StatusRunning}
+	events <- process.StateEvent{Name: "api", OldState: process.StatusRunning, NewState: process.StatusRetrying}
+	events <- process.StateEvent{Name: "api", OldState: process.StatusRetrying, NewState: process.StatusStarting}
+	events <- process.StateEvent{Name: "api", OldState: process.StatusStarting, NewState: process.StatusRunning}
+	events <- process.StateEvent{Name: "web", OldState: process.StatusFailed, NewState: process.StatusFailed, Error: "max retries exhausted (exit code 1)"}
+	close(events)
+	d.Run(context.Background(), events)
+
+	require.Eventually(t, func() bool { return len(rec.received()) == 2 }, 2*time.Second, 10*time.Millisecond)
+	got := make(map[string]Payload)
+	for _, p := range rec.received() {
+		got[p.Name] = p
+	}
+	assert.Equal(t, process.StatusStarting, got["api"].OldState)
+	assert.Equal(t, process.StatusRunning, got["api"].NewState)
+	assert.Equal(t, "max retries exhausted (exit code 1)", got["web"].Error)
+	assert.False(t, got["web"].Time.IsZero())
+}
+
+func TestDispatcher_RetriesServerErrors(t *testing.T) {
+	rec := &recorder{failures: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	d := NewDispatcher([]config.Webhook{{URL: srv.URL, Events: []string{"stopped"}}})
+	d.backoff = 10 * time.Millisecond
+	d.dispatch(context.Background(), process.StateEvent{Name: "db", OldState: process.StatusRunning, NewState: process.StatusStopped})
+
+	require.Eventually(t, func() bool { return len(rec.received()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	rec.mu.Lock()
+	assert.Equal(t, 3, rec.calls)
+	rec.mu.Unlock()
+}
+
+func TestDispatcher_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	d := NewDispatcher(nil)
+	hook := config.Webhook{URL: srv.URL, Timeout: config.Duration(50 * time.Millisecond)}
+
+	start := time.Now()
+	retry, err := d.send(context.Background(), hook, []byte("{}"))
+	assert.Error(t, err)
+	assert.True(t, retry)
+	assert.Less(t, time.Since(start), time.Second)
+}